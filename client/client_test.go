@@ -0,0 +1,143 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/client"
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/server"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestClient(t *testing.T, folders []folder.Folder) *client.ClientWithResponses {
+	ts := httptest.NewServer(server.New(folder.NewDriver(folders)))
+	t.Cleanup(ts.Close)
+
+	c, err := client.NewClientWithResponses(ts.URL+"/", client.WithHTTPClient(ts.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func Test_client(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	org := client.Org(defaultOrgID)
+	c := newTestClient(t, []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+	})
+	ctx := context.Background()
+
+	// Each call builds on the previous one, so they run in order against the same server
+	parent := "bravo"
+	created, err := c.CreateFolderWithResponse(ctx, org, client.CreateFolderJSONRequestBody{Name: "charlie", Parent: &parent})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, created.StatusCode())
+	assert.Len(t, *created.JSON201, 4)
+
+	moved, err := c.MoveFolderWithResponse(ctx, org, "bravo", client.MoveFolderJSONRequestBody{Dst: "golf"})
+	assert.NoError(t, err)
+	assert.Contains(t, *moved.JSON200, client.Folder{Name: "charlie", Paths: "golf.bravo.charlie", OrgId: org})
+
+	renamed, err := c.RenameFolderWithResponse(ctx, org, "charlie", client.RenameFolderJSONRequestBody{Name: "delta"})
+	assert.NoError(t, err)
+	assert.Contains(t, *renamed.JSON200, client.Folder{Name: "delta", Paths: "golf.bravo.delta", OrgId: org})
+
+	children, err := c.GetAllChildFoldersWithResponse(ctx, org, "golf")
+	assert.NoError(t, err)
+	assert.Equal(t, client.Folders{
+		{Name: "bravo", Paths: "golf.bravo", OrgId: org},
+		{Name: "delta", Paths: "golf.bravo.delta", OrgId: org},
+	}, *children.JSON200)
+
+	ancestors, err := c.GetAncestorFoldersWithResponse(ctx, org, "delta")
+	assert.NoError(t, err)
+	assert.Equal(t, client.Folders{
+		{Name: "golf", Paths: "golf", OrgId: org},
+		{Name: "bravo", Paths: "golf.bravo", OrgId: org},
+	}, *ancestors.JSON200)
+
+	deleted, err := c.DeleteFolderWithResponse(ctx, org, "golf")
+	assert.NoError(t, err)
+	assert.Equal(t, client.Folders{{Name: "alpha", Paths: "alpha", OrgId: org}}, *deleted.JSON200)
+
+	listed, err := c.GetFoldersByOrgIDWithResponse(ctx, client.Org(uuid.Must(uuid.NewV4())))
+	assert.NoError(t, err)
+	assert.Equal(t, client.Folders{}, *listed.JSON200)
+}
+
+func Test_client_Error(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	org := client.Org(defaultOrgID)
+	c := newTestClient(t, []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+	})
+	ctx := context.Background()
+
+	// Each call returns the status and the decoded error for it
+	tests := [...]struct {
+		testName string
+		call     func() (int, *client.Error, error)
+		status   int
+		message  string
+	}{
+		{
+			testName: "Not found",
+			call: func() (int, *client.Error, error) {
+				res, err := c.GetAllChildFoldersWithResponse(ctx, org, "zulu")
+				return res.StatusCode(), res.JSON404, err
+			},
+			status:  http.StatusNotFound,
+			message: "folder does not exist in the specified organisation",
+		},
+		{
+			testName: "Conflict",
+			call: func() (int, *client.Error, error) {
+				res, err := c.RenameFolderWithResponse(ctx, org, "bravo", client.RenameFolderJSONRequestBody{Name: "alpha"})
+				return res.StatusCode(), res.JSON409, err
+			},
+			status:  http.StatusConflict,
+			message: "folder already exists in the specified organisation",
+		},
+		{
+			testName: "Unprocessable",
+			call: func() (int, *client.Error, error) {
+				res, err := c.MoveFolderWithResponse(ctx, org, "alpha", client.MoveFolderJSONRequestBody{Dst: "bravo"})
+				return res.StatusCode(), res.JSON422, err
+			},
+			status:  http.StatusUnprocessableEntity,
+			message: "cannot move a folder to a child of itself",
+		},
+		{
+			testName: "Names are escaped",
+			call: func() (int, *client.Error, error) {
+				res, err := c.DeleteFolderWithResponse(ctx, org, "a/b?c")
+				return res.StatusCode(), res.JSON404, err
+			},
+			status:  http.StatusNotFound,
+			message: "folder does not exist in the specified organisation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			status, get, err := tt.call()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, status)
+			if assert.NotNil(t, get) {
+				assert.Equal(t, tt.message, get.Error)
+			}
+		})
+	}
+}
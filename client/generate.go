@@ -0,0 +1,8 @@
+// Package client calls the folder HTTP API described by server/openapi.yaml.
+//
+// The code in client.gen.go is generated from the document, rebuild it after changing the API with
+//
+//	go generate ./client
+package client
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.4.1 -config oapi-codegen.yaml ../server/openapi.yaml
@@ -0,0 +1,301 @@
+package folder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// AuditEntry records who made a change through the driver and what it touched.
+type AuditEntry struct {
+	Time  time.Time `json:"time"`
+	Actor string    `json:"actor"`
+	Op    Op        `json:"op"`
+	OrgId uuid.UUID `json:"org_id"`
+	// Name of the folder after the change
+	Name string `json:"name"`
+	// OldName is the name of a renamed folder before the change
+	OldName string `json:"old_name,omitempty"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+	// Descendants is the number of folders below the target that the change also rewrote or deleted
+	Descendants int `json:"descendants"`
+}
+
+// AuditQuery picks audit entries, the zero value matches everything.
+type AuditQuery struct {
+	OrgID uuid.UUID
+	// Name matches changes made to the folder with this name, before or after a rename
+	Name string
+	// Path matches changes to the folder at this path or to any folder above it, before or after the change,
+	// i.e. every change that moved the folder along with its parent
+	Path string
+	// Since and Until bound the time of the change, Since inclusive and Until exclusive, zero values are open
+	Since time.Time
+	Until time.Time
+}
+
+func (q AuditQuery) Match(e AuditEntry) bool {
+	switch {
+	case q.OrgID != uuid.Nil && q.OrgID != e.OrgId:
+		return false
+	case q.Name != "" && q.Name != e.Name && q.Name != e.OldName:
+		return false
+	case q.Path != "" && !inSubtree(q.Path, e.From) && !inSubtree(q.Path, e.To):
+		return false
+	case !q.Since.IsZero() && e.Time.Before(q.Since):
+		return false
+	case !q.Until.IsZero() && !e.Time.Before(q.Until):
+		return false
+	}
+	return true
+}
+
+// AuditSink stores audit entries.
+type AuditSink interface {
+	// Record stores an entry, it returns once the entry is durable.
+	Record(entry AuditEntry) error
+	// Query returns the entries matching q, oldest first.
+	Query(q AuditQuery) ([]AuditEntry, error)
+}
+
+// Auditor records every change made to a driver in an audit sink.
+// Changes are attributed to an actor by making them through the driver returned by As.
+type Auditor struct {
+	driver auditable
+	sink   AuditSink
+}
+
+type auditable interface {
+	IDriver
+	observe(fn func(origin any, m Mutation, events []Event))
+	as(origin any) IDriver
+}
+
+// One change made as an actor, passed to the driver as the origin of the change
+// Recording runs in the caller's goroutine before the driver returns, so it needs no lock.
+type auditCall struct {
+	actor string
+	err   error
+}
+
+// Starts auditing the changes made to a driver
+// Input: driver from this package, audit sink
+// Output: auditor, error
+// Errors: Driver that does not publish its changes
+func NewAuditor(d IDriver, sink AuditSink) (*Auditor, error) {
+	driver, ok := d.(auditable)
+	if !ok {
+		return nil, fmt.Errorf("cannot audit a %T", d)
+	}
+
+	a := &Auditor{driver: driver, sink: sink}
+	driver.observe(a.record)
+	return a, nil
+}
+
+// Called by the driver after every change
+// The change carries its actor when it was made through As, anything else is recorded without one.
+func (a *Auditor) record(origin any, m Mutation, events []Event) {
+	call, ok := origin.(*auditCall)
+
+	entry := AuditEntry{
+		Time:  time.Now().UTC(),
+		Op:    m.Op,
+		OrgId: m.OrgId,
+		Name:  m.Name,
+		From:  m.From,
+		To:    m.To,
+	}
+	if ok {
+		entry.Actor = call.actor
+	}
+	if m.Op == OpRename {
+		entry.OldName = pathName(m.From)
+	}
+	if len(events) > 0 && m.Op != OpCreate {
+		entry.Descendants = len(events) - 1
+	}
+	err := a.sink.Record(entry)
+
+	if ok {
+		call.err = errors.Join(call.err, err)
+	}
+}
+
+// As returns the driver with every change attributed to an actor.
+// Changes made to the driver directly are recorded without an actor.
+func (a *Auditor) As(actor string) IDriver {
+	return &auditedDriver{IDriver: a.driver, auditor: a, actor: actor}
+}
+
+// Query returns the entries matching q, oldest first.
+func (a *Auditor) Query(q AuditQuery) ([]AuditEntry, error) {
+	return a.sink.Query(q)
+}
+
+type auditedDriver struct {
+	IDriver
+	auditor *Auditor
+	actor   string
+}
+
+// Makes a change as the driver's actor
+// The change is made through a view of the driver carrying a token for this call, so recording it finds the actor
+// whatever else changes the driver at the same time.
+// It has already been made when recording fails, so the folders are returned along with the error.
+func (d *auditedDriver) change(op func(IDriver) ([]Folder, error)) ([]Folder, error) {
+	call := &auditCall{actor: d.actor}
+	folders, err := op(d.auditor.driver.as(call))
+	if err != nil {
+		return nil, err
+	}
+	if call.err != nil {
+		return folders, fmt.Errorf("change made but not audited: %w", call.err)
+	}
+	return folders, nil
+}
+
+func (d *auditedDriver) MoveFolder(name string, dst string) ([]Folder, error) {
+	return d.change(func(p IDriver) ([]Folder, error) { return p.MoveFolder(name, dst) })
+}
+
+func (d *auditedDriver) RenameFolder(name string, newName string) ([]Folder, error) {
+	return d.change(func(p IDriver) ([]Folder, error) { return p.RenameFolder(name, newName) })
+}
+
+func (d *auditedDriver) DeleteFolder(name string) ([]Folder, error) {
+	return d.change(func(p IDriver) ([]Folder, error) { return p.DeleteFolder(name) })
+}
+
+func (d *auditedDriver) MoveFolderInOrg(orgID uuid.UUID, name string, dst string) ([]Folder, error) {
+	return d.change(func(p IDriver) ([]Folder, error) { return p.MoveFolderInOrg(orgID, name, dst) })
+}
+
+func (d *auditedDriver) RenameFolderInOrg(orgID uuid.UUID, name string, newName string) ([]Folder, error) {
+	return d.change(func(p IDriver) ([]Folder, error) { return p.RenameFolderInOrg(orgID, name, newName) })
+}
+
+func (d *auditedDriver) DeleteFolderInOrg(orgID uuid.UUID, name string) ([]Folder, error) {
+	return d.change(func(p IDriver) ([]Folder, error) { return p.DeleteFolderInOrg(orgID, name) })
+}
+
+func (d *auditedDriver) CreateFolder(orgID uuid.UUID, name string, parent string) ([]Folder, error) {
+	return d.change(func(p IDriver) ([]Folder, error) { return p.CreateFolder(orgID, name, parent) })
+}
+
+// MemoryAuditSink keeps audit entries in memory only.
+type MemoryAuditSink struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+}
+
+func (s *MemoryAuditSink) Record(entry AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *MemoryAuditSink) Query(q AuditQuery) ([]AuditEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := []AuditEntry{}
+	for _, entry := range s.entries {
+		if q.Match(entry) {
+			res = append(res, entry)
+		}
+	}
+	return res, nil
+}
+
+// FileAuditSink appends audit entries to a newline-delimited JSON file.
+// Every entry is synced to disk before Record returns.
+type FileAuditSink struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// Opens or creates an audit log file for appending, dropping an entry torn by a crash mid-write
+// Input: path of the audit log
+// Output: file audit sink, error
+// Errors: IO errors
+func OpenFileAuditSink(path string) (*FileAuditSink, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := io.ReadAll(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if end := bytes.LastIndexByte(b, '\n') + 1; end < len(b) {
+		if err := file.Truncate(int64(end)); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+
+	return &FileAuditSink{path: path, file: file}, nil
+}
+
+func (s *FileAuditSink) Record(entry AuditEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+// Reads the whole log for the matching entries
+// Input: audit query
+// Output: matching entries oldest first, error
+// Errors: IO errors, corrupted entries
+func (s *FileAuditSink) Query(q AuditQuery) ([]AuditEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	res := []AuditEntry{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("audit log line %d: %w", line, err)
+		}
+		if q.Match(entry) {
+			res = append(res, entry)
+		}
+	}
+	return res, scanner.Err()
+}
+
+func (s *FileAuditSink) Close() error {
+	return s.file.Close()
+}
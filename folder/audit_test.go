@@ -0,0 +1,219 @@
+package folder_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_Auditor(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+	}
+
+	for _, d := range testDrivers {
+		t.Run(d.name, func(t *testing.T) {
+			f := d.new(t, example1)
+			auditor, err := folder.NewAuditor(f, &folder.MemoryAuditSink{})
+			assert.NoError(t, err)
+
+			start := time.Now()
+			_, err = auditor.As("ada").MoveFolder("bravo", "golf")
+			assert.NoError(t, err)
+			_, err = auditor.As("grace").CreateFolder(defaultOrgID, "hotel", "charlie")
+			assert.NoError(t, err)
+			_, err = auditor.As("ada").MoveFolder("golf", "charlie")
+			assert.Error(t, err)
+			_, err = auditor.As("grace").RenameFolderInOrg(defaultOrgID, "charlie", "india")
+			assert.NoError(t, err)
+			_, err = f.DeleteFolder("alpha")
+			assert.NoError(t, err)
+
+			entries, err := auditor.Query(folder.AuditQuery{})
+			assert.NoError(t, err)
+			for i := range entries {
+				assert.WithinRange(t, entries[i].Time, start, time.Now())
+				entries[i].Time = time.Time{}
+			}
+			assert.Equal(t, []folder.AuditEntry{
+				{Actor: "ada", Op: folder.OpMove, OrgId: defaultOrgID, Name: "bravo", From: "alpha.bravo", To: "golf.bravo", Descendants: 1},
+				{Actor: "grace", Op: folder.OpCreate, OrgId: defaultOrgID, Name: "hotel", To: "golf.bravo.charlie.hotel"},
+				{Actor: "grace", Op: folder.OpRename, OrgId: defaultOrgID, Name: "india", OldName: "charlie", From: "golf.bravo.charlie", To: "golf.bravo.india", Descendants: 1},
+				{Actor: "", Op: folder.OpDelete, OrgId: defaultOrgID, Name: "alpha", From: "alpha"},
+			}, entries)
+
+			// Changes to hotel include moving and renaming its ancestors
+			entries, err = auditor.Query(folder.AuditQuery{Path: "golf.bravo.charlie.hotel"})
+			assert.NoError(t, err)
+			assert.Len(t, entries, 3)
+
+			// A folder's changes can be found by the name it had before a rename
+			entries, err = auditor.Query(folder.AuditQuery{Name: "charlie"})
+			assert.NoError(t, err)
+			if assert.Len(t, entries, 1) {
+				assert.Equal(t, folder.OpRename, entries[0].Op)
+			}
+
+			entries, err = auditor.Query(folder.AuditQuery{Name: "alpha", Until: start})
+			assert.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+}
+
+// Sink that holds the first entry until it is released
+type blockingAuditSink struct {
+	folder.MemoryAuditSink
+	held      atomic.Bool
+	recording chan struct{}
+	release   chan struct{}
+}
+
+func (s *blockingAuditSink) Record(entry folder.AuditEntry) error {
+	if s.held.CompareAndSwap(false, true) {
+		close(s.recording)
+		<-s.release
+	}
+	return s.MemoryAuditSink.Record(entry)
+}
+
+func Test_folder_Auditor_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+	}
+
+	// The SQLite driver can be changed from several goroutines
+	f := testDrivers[1].new(t, example1)
+	sink := &blockingAuditSink{recording: make(chan struct{}), release: make(chan struct{})}
+	auditor, err := folder.NewAuditor(f, sink)
+	assert.NoError(t, err)
+
+	done := make(chan error)
+	go func() {
+		_, err := auditor.As("ada").MoveFolder("bravo", "golf")
+		done <- err
+	}()
+
+	// A direct change made while ada's is being recorded waits for it, and is not hers
+	<-sink.recording
+	go func() {
+		_, err := f.DeleteFolder("alpha")
+		done <- err
+	}()
+	close(sink.release)
+	assert.NoError(t, <-done)
+	assert.NoError(t, <-done)
+
+	entries, err := auditor.Query(folder.AuditQuery{})
+	assert.NoError(t, err)
+	for i := range entries {
+		entries[i].Time = time.Time{}
+	}
+	assert.ElementsMatch(t, []folder.AuditEntry{
+		{Actor: "ada", Op: folder.OpMove, OrgId: defaultOrgID, Name: "bravo", From: "alpha.bravo", To: "golf.bravo"},
+		{Actor: "", Op: folder.OpDelete, OrgId: defaultOrgID, Name: "alpha", From: "alpha"},
+	}, entries)
+}
+
+func Test_folder_Auditor_SameChange(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+	}
+
+	for _, d := range testDrivers {
+		t.Run(d.name, func(t *testing.T) {
+			f := d.new(t, example1)
+			auditor, err := folder.NewAuditor(f, &folder.MemoryAuditSink{})
+			assert.NoError(t, err)
+
+			// Actors racing to make the same change, only the one whose change is made is recorded
+			actors := []string{"ada", "grace", "alan", "edsger"}
+			errs := make([]error, len(actors))
+			var wg sync.WaitGroup
+			for i, actor := range actors {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, errs[i] = auditor.As(actor).CreateFolder(defaultOrgID, "bravo", "alpha")
+				}()
+			}
+			wg.Wait()
+
+			made := ""
+			for i, err := range errs {
+				if err == nil {
+					made = actors[i]
+				}
+			}
+			entries, err := auditor.Query(folder.AuditQuery{})
+			assert.NoError(t, err)
+			if assert.Len(t, entries, 1) {
+				assert.Equal(t, made, entries[0].Actor)
+			}
+		})
+	}
+}
+
+func Test_folder_FileAuditSink(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	monday := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	sink, err := folder.OpenFileAuditSink(path)
+	assert.NoError(t, err)
+	for i, name := range []string{"alpha", "bravo", "charlie"} {
+		err := sink.Record(folder.AuditEntry{
+			Time: monday.Add(time.Duration(i) * 24 * time.Hour), Actor: "ada", Op: folder.OpCreate, OrgId: defaultOrgID, Name: name, To: name,
+		})
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, sink.Close())
+
+	// Simulate a crash in the middle of writing an entry
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	assert.NoError(t, err)
+	_, err = file.WriteString(`{"time":"2024-03-08T09:00:00Z","act`)
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+
+	sink, err = folder.OpenFileAuditSink(path)
+	assert.NoError(t, err)
+	defer sink.Close()
+	assert.NoError(t, sink.Record(folder.AuditEntry{Time: monday.Add(96 * time.Hour), Actor: "grace", Op: folder.OpDelete, OrgId: defaultOrgID, Name: "alpha", From: "alpha"}))
+
+	entries, err := sink.Query(folder.AuditQuery{Since: monday.Add(24 * time.Hour), Until: monday.Add(96 * time.Hour)})
+	assert.NoError(t, err)
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+	assert.Equal(t, []string{"bravo", "charlie"}, names)
+
+	entries, err = sink.Query(folder.AuditQuery{Name: "alpha"})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "grace", entries[1].Actor)
+}
@@ -0,0 +1,49 @@
+package folder
+
+import (
+	"fmt"
+
+	"github.com/gofrs/uuid"
+)
+
+// Create a new folder, either at the root or inside an existing folder
+// Input: orgID, folder name, parent folder name (empty for a root folder)
+// Output: slice of folders, IO errors
+// Errors: Invalid name, non-existent parent, name already used in the organisation
+func (f *driver) CreateFolder(orgID uuid.UUID, name string, parent string) ([]Folder, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := checkFolderName(name); err != nil {
+		return nil, err
+	}
+
+	path := name
+	parentFound := parent == ""
+	for _, folder := range f.folders {
+		if folder.OrgId != orgID {
+			continue
+		}
+		if folder.Name == name {
+			return nil, fmt.Errorf("%w in the specified organisation", ErrFolderExists)
+		}
+		if parent != "" && folder.Name == parent {
+			path = folder.Paths + "." + name
+			parentFound = true
+		}
+	}
+	if !parentFound {
+		return nil, fmt.Errorf("parent %w in the specified organisation", ErrFolderNotFound)
+	}
+
+	m := Mutation{Op: OpCreate, OrgId: orgID, Name: name, To: path}
+	events, err := f.persist(m)
+	if err != nil {
+		return nil, err
+	}
+
+	f.folders = append(f.folders, Folder{Name: name, OrgId: orgID, Paths: path})
+	f.publish(m, events)
+
+	return append([]Folder{}, f.folders...), nil
+}
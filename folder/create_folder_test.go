@@ -0,0 +1,138 @@
+package folder_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_CreateFolder(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	secondaryOrgID := uuid.Must(uuid.NewV4())
+
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+	}
+
+	tests := [...]struct {
+		testName string
+		orgID    uuid.UUID
+		name     string
+		parent   string
+		want     []folder.Folder
+		err      string
+	}{
+		{
+			testName: "Create nested folder",
+			orgID:    defaultOrgID,
+			name:     "charlie",
+			parent:   "bravo",
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+				{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+				{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+			},
+		},
+		{
+			testName: "Create root folder",
+			orgID:    secondaryOrgID,
+			name:     "alpha",
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+				{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+				{Name: "alpha", Paths: "alpha", OrgId: secondaryOrgID},
+			},
+		},
+		{
+			testName: "Name already used in organisation",
+			orgID:    defaultOrgID,
+			name:     "bravo",
+			err:      "folder already exists in the specified organisation",
+		},
+		{
+			testName: "Parent in another organisation",
+			orgID:    defaultOrgID,
+			name:     "golf",
+			parent:   "foxtrot",
+			err:      "does not exist in the specified organisation",
+		},
+		{
+			testName: "Invalid name",
+			orgID:    defaultOrgID,
+			name:     "go.lf",
+			err:      "contains '.'",
+		},
+	}
+
+	for _, tt := range tests {
+		for _, d := range testDrivers {
+			t.Run(d.name+"/"+tt.testName, func(t *testing.T) {
+				f := d.new(t, example1)
+				get, err := f.CreateFolder(tt.orgID, tt.name, tt.parent)
+				if tt.err != "" {
+					assert.ErrorContains(t, err, tt.err)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, get)
+			})
+		}
+	}
+}
+
+func Test_folder_CreateFolder_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+	}
+
+	for _, d := range testDrivers {
+		t.Run(d.name, func(t *testing.T) {
+			f := d.new(t, example1)
+
+			// Only one of the changes racing for a name makes it, whatever reads run alongside
+			var wg sync.WaitGroup
+			errs := make(chan error, 8)
+			for range 8 {
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+					_, err := f.CreateFolder(defaultOrgID, "bravo", "alpha")
+					errs <- err
+				}()
+				go func() {
+					defer wg.Done()
+					_, err := f.GetAllChildFolders(defaultOrgID, "alpha")
+					assert.NoError(t, err)
+				}()
+			}
+			wg.Wait()
+			close(errs)
+
+			created := 0
+			for err := range errs {
+				if err == nil {
+					created++
+				} else {
+					assert.ErrorIs(t, err, folder.ErrFolderExists)
+				}
+			}
+			assert.Equal(t, 1, created)
+			assert.Equal(t, []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+			}, f.GetFoldersByOrgID(defaultOrgID))
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package folder
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+// The columns every folder CSV has, any other column is kept as an extra
+var csvColumns = []string{"name", "org_id", "paths"}
+
+// CSVRecord is a folder read from or written to CSV along with any extra columns.
+type CSVRecord struct {
+	Folder
+	Extra map[string]string
+}
+
+// CSVReader streams folders from CSV with a header row.
+// Rows are numbered the way a spreadsheet shows them, the header is row 1.
+type CSVReader struct {
+	reader *csv.Reader
+	index  map[string]int
+	extra  []string
+}
+
+// Creates a CSV reader and reads the header row
+// Input: reader
+// Output: CSV reader, error
+// Errors: IO errors, missing or duplicated columns
+func NewCSVReader(r io.Reader) (*CSVReader, error) {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("row 1: missing header")
+	} else if err != nil {
+		return nil, err
+	}
+
+	index := map[string]int{}
+	extra := []string{}
+	for i, column := range header {
+		column = strings.TrimSpace(column)
+		if _, ok := index[column]; ok {
+			return nil, fmt.Errorf("row 1: duplicate column %q", column)
+		}
+		index[column] = i
+		if !isCSVColumn(column) {
+			extra = append(extra, column)
+		}
+	}
+	for _, column := range csvColumns {
+		if _, ok := index[column]; !ok {
+			return nil, fmt.Errorf("row 1: missing column %q", column)
+		}
+	}
+
+	return &CSVReader{reader: reader, index: index, extra: extra}, nil
+}
+
+func isCSVColumn(column string) bool {
+	for _, c := range csvColumns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// Extra returns the names of the columns that are not part of a folder, in file order.
+func (r *CSVReader) Extra() []string {
+	return r.extra
+}
+
+// Reads the next row, returning io.EOF once every row has been read
+// Input: None
+// Output: record, error
+// Errors: IO errors, malformed CSV, invalid org_id, empty name or paths, paths not ending in the name
+func (r *CSVReader) Read() (CSVRecord, error) {
+	row, err := r.reader.Read()
+	if err != nil {
+		return CSVRecord{}, err
+	}
+	line, _ := r.reader.FieldPos(0)
+
+	orgID, err := uuid.FromString(strings.TrimSpace(row[r.index["org_id"]]))
+	if err != nil {
+		return CSVRecord{}, fmt.Errorf("row %d: invalid org_id: %w", line, err)
+	}
+
+	folder := Folder{
+		Name:  strings.TrimSpace(row[r.index["name"]]),
+		OrgId: orgID,
+		Paths: strings.TrimSpace(row[r.index["paths"]]),
+	}
+	if folder.Name == "" {
+		return CSVRecord{}, fmt.Errorf("row %d: empty name", line)
+	} else if folder.Paths == "" {
+		return CSVRecord{}, fmt.Errorf("row %d: empty paths", line)
+	} else if folder.Paths != folder.Name && !strings.HasSuffix(folder.Paths, "."+folder.Name) {
+		return CSVRecord{}, fmt.Errorf("row %d: paths %q does not end with name %q", line, folder.Paths, folder.Name)
+	}
+
+	record := CSVRecord{Folder: folder}
+	if len(r.extra) > 0 {
+		record.Extra = make(map[string]string, len(r.extra))
+		for _, column := range r.extra {
+			record.Extra[column] = row[r.index[column]]
+		}
+	}
+	return record, nil
+}
+
+// CSVWriter streams folders to CSV, writing the header before the first row.
+type CSVWriter struct {
+	writer      *csv.Writer
+	extra       []string
+	wroteHeader bool
+}
+
+// Creates a CSV writer with the given extra columns after name, org_id and paths
+func NewCSVWriter(w io.Writer, extra ...string) *CSVWriter {
+	return &CSVWriter{writer: csv.NewWriter(w), extra: extra}
+}
+
+// Write buffers a single row, extra columns missing from the record are left empty.
+func (w *CSVWriter) Write(record CSVRecord) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	row := []string{record.Name, record.OrgId.String(), record.Paths}
+	for _, column := range w.extra {
+		row = append(row, record.Extra[column])
+	}
+	return w.writer.Write(row)
+}
+
+// Flush writes any buffered rows, writing the header if no rows were written.
+func (w *CSVWriter) Flush() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *CSVWriter) writeHeader() error {
+	if w.wroteHeader {
+		return nil
+	}
+	w.wroteHeader = true
+	return w.writer.Write(append(append([]string{}, csvColumns...), w.extra...))
+}
+
+// Reads every folder from CSV, dropping extra columns
+// Input: reader
+// Output: slice of folders, error
+// Errors: Errors from NewCSVReader and CSVReader.Read
+func ReadFoldersCSV(r io.Reader) ([]Folder, error) {
+	reader, err := NewCSVReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	folders := []Folder{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return folders, nil
+		} else if err != nil {
+			return nil, err
+		}
+		folders = append(folders, record.Folder)
+	}
+}
+
+// Writes folders as CSV with a header row
+// Input: writer, folders
+// Output: error
+// Errors: IO errors
+func WriteFoldersCSV(w io.Writer, folders []Folder) error {
+	writer := NewCSVWriter(w)
+	for _, folder := range folders {
+		if err := writer.Write(CSVRecord{Folder: folder}); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,107 @@
+package folder_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_CSV_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo, inc", Paths: "alpha.bravo, inc", OrgId: defaultOrgID},
+	}
+
+	var b bytes.Buffer
+	assert.NoError(t, folder.WriteFoldersCSV(&b, folders))
+	assert.Equal(t, "name,org_id,paths\n"+
+		"alpha,"+folder.DefaultOrgID+",alpha\n"+
+		`"bravo, inc",`+folder.DefaultOrgID+`,"alpha.bravo, inc"`+"\n", b.String())
+
+	get, err := folder.ReadFoldersCSV(&b)
+	assert.NoError(t, err)
+	assert.Equal(t, folders, get)
+}
+
+func Test_folder_CSV_ExtraColumns(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	input := "owner,paths,name,org_id\n" +
+		"sam,alpha,alpha," + folder.DefaultOrgID + "\n"
+
+	reader, err := folder.NewCSVReader(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"owner"}, reader.Extra())
+
+	record, err := reader.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, folder.CSVRecord{
+		Folder: folder.Folder{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		Extra:  map[string]string{"owner": "sam"},
+	}, record)
+
+	var b bytes.Buffer
+	writer := folder.NewCSVWriter(&b, reader.Extra()...)
+	assert.NoError(t, writer.Write(record))
+	assert.NoError(t, writer.Flush())
+	assert.Equal(t, "name,org_id,paths,owner\nalpha,"+folder.DefaultOrgID+",alpha,sam\n", b.String())
+}
+
+func Test_folder_CSV_Error(t *testing.T) {
+	t.Parallel()
+
+	header := "name,org_id,paths\n"
+	valid := "alpha," + folder.DefaultOrgID + ",alpha\n"
+
+	tests := [...]struct {
+		testName string
+		input    string
+		want     string
+	}{
+		{
+			testName: "Empty input",
+			input:    "",
+			want:     "row 1: missing header",
+		},
+		{
+			testName: "Missing column",
+			input:    "name,paths\n",
+			want:     `row 1: missing column "org_id"`,
+		},
+		{
+			testName: "Invalid org_id",
+			input:    header + valid + "bravo,not-a-uuid,alpha.bravo\n",
+			want:     "row 3: invalid org_id",
+		},
+		{
+			testName: "Empty name",
+			input:    header + valid + "," + folder.DefaultOrgID + ",alpha.bravo\n",
+			want:     "row 3: empty name",
+		},
+		{
+			testName: "Paths not ending in name",
+			input:    header + valid + valid + "bravo," + folder.DefaultOrgID + ",alpha.charlie\n",
+			want:     `row 4: paths "alpha.charlie" does not end with name "bravo"`,
+		},
+		{
+			testName: "Wrong number of fields",
+			input:    header + "bravo\n",
+			want:     "line 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			_, err := folder.ReadFoldersCSV(strings.NewReader(tt.input))
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
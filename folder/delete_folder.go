@@ -0,0 +1,65 @@
+package folder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+// Delete a folder along with all of its children
+// Input: folder name
+// Output: slice of remaining folders, IO errors
+// Errors: Non-existent folder
+func (f *driver) DeleteFolder(name string) ([]Folder, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	index := f.findFolderIndex(name)
+	if index == -1 {
+		return nil, ErrFolderNotFound
+	}
+	return f.deleteFolder(index)
+}
+
+// Delete a folder of an organisation along with all of its children
+// Input: organisation ID, folder name
+// Output: slice of remaining folders, IO errors
+// Errors: Non-existent folder in the organisation
+func (f *driver) DeleteFolderInOrg(orgID uuid.UUID, name string) ([]Folder, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	index := f.findFolderIndexInOrg(orgID, name)
+	if index == -1 {
+		return nil, fmt.Errorf("%w in the specified organisation", ErrFolderNotFound)
+	}
+	return f.deleteFolder(index)
+}
+
+// Deletes the folder at an index and its children
+// Input: index of the folder
+// Output: slice of remaining folders, IO errors
+// Errors: Errors from the store
+func (f *driver) deleteFolder(index int) ([]Folder, error) {
+	node := f.folders[index]
+	m := Mutation{Op: OpDelete, OrgId: node.OrgId, Name: node.Name, From: node.Paths}
+	events, err := f.persist(m)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep every folder outside of the deleted subtree
+	remaining := make([]Folder, 0, len(f.folders))
+	for _, folder := range f.folders {
+		inSubtree := folder.OrgId == node.OrgId &&
+			(folder.Paths == node.Paths || strings.HasPrefix(folder.Paths, node.Paths+"."))
+		if !inSubtree {
+			remaining = append(remaining, folder)
+		}
+	}
+	f.folders = remaining
+	f.publish(m, events)
+
+	return append([]Folder{}, f.folders...), nil
+}
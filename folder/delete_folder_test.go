@@ -0,0 +1,95 @@
+package folder_test
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_DeleteFolder(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	secondaryOrgID := uuid.Must(uuid.NewV4())
+
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+		{Name: "delta", Paths: "alpha.delta", OrgId: defaultOrgID},
+		{Name: "foxtrot", Paths: "alpha", OrgId: secondaryOrgID},
+	}
+
+	tests := [...]struct {
+		testName string
+		name     string
+		want     []folder.Folder
+	}{
+		{
+			testName: "Delete leaf folder",
+			name:     "charlie",
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+				{Name: "delta", Paths: "alpha.delta", OrgId: defaultOrgID},
+				{Name: "foxtrot", Paths: "alpha", OrgId: secondaryOrgID},
+			},
+		},
+		{
+			testName: "Delete subtree in one organisation only",
+			name:     "alpha",
+			want: []folder.Folder{
+				{Name: "foxtrot", Paths: "alpha", OrgId: secondaryOrgID},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, d := range testDrivers {
+			t.Run(d.name+"/"+tt.testName, func(t *testing.T) {
+				f := d.new(t, example1)
+				get, err := f.DeleteFolder(tt.name)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, get)
+			})
+		}
+	}
+
+	for _, d := range testDrivers {
+		t.Run(d.name+"/Folder does not exist", func(t *testing.T) {
+			f := d.new(t, example1)
+			_, err := f.DeleteFolder("invalid_folder")
+			assert.ErrorContains(t, err, "folder does not exist")
+		})
+	}
+}
+
+func Test_folder_DeleteFolderInOrg(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	secondaryOrgID := uuid.Must(uuid.NewV4())
+
+	// The secondary organisation comes last, so DeleteFolder would pick its alpha
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+		{Name: "alpha", Paths: "alpha", OrgId: secondaryOrgID},
+	}
+
+	for _, d := range testDrivers {
+		t.Run(d.name, func(t *testing.T) {
+			f := d.new(t, example1)
+			get, err := f.DeleteFolderInOrg(defaultOrgID, "alpha")
+			assert.NoError(t, err)
+			assert.Equal(t, example1[2:], get)
+
+			_, err = f.DeleteFolderInOrg(secondaryOrgID, "golf")
+			assert.ErrorIs(t, err, folder.ErrFolderNotFound)
+			assert.ErrorContains(t, err, "folder does not exist in the specified organisation")
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package folder
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+type ChangeType string
+
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeRemoved ChangeType = "removed"
+	// ChangeMoved is a folder with a new parent in the same organisation
+	ChangeMoved   ChangeType = "moved"
+	ChangeRenamed ChangeType = "renamed"
+	// ChangeMovedOrg is a folder that left its organisation for another one
+	ChangeMovedOrg ChangeType = "moved_org"
+)
+
+// Change is one difference between two folder sets.
+// It covers the subtree below the folder, so the descendants that came along are not listed separately.
+// From is empty for added folders and To for removed ones.
+type Change struct {
+	Type ChangeType `json:"type"`
+	// OrgId is the organisation after the change, or before it for removed folders
+	OrgId     uuid.UUID `json:"org_id"`
+	FromOrgId uuid.UUID `json:"from_org_id"`
+	Name      string    `json:"name"`
+	OldName   string    `json:"old_name,omitempty"`
+	From      string    `json:"from,omitempty"`
+	To        string    `json:"to,omitempty"`
+	// Descendants is the number of folders below the folder that the change also covers
+	Descendants int `json:"descendants"`
+}
+
+type folderKey struct {
+	orgID uuid.UUID
+	name  string
+}
+
+type pathKey struct {
+	orgID uuid.UUID
+	paths string
+}
+
+// Works out how one folder set became another
+// A folder keeps its identity through a change by staying at the same path, by its name when the name is used
+// once in its organisation on both sides, or by its name alone when it is the only folder with that name
+// to leave one organisation and join another.
+// A folder removed and another added under the same parent are taken to be a rename,
+// as long as the unmatched folders below them are at the same relative paths.
+// Input: folders before, folders after
+// Output: changes, ordered as the folders before and then the added folders after
+func Diff(before []Folder, after []Folder) []Change {
+	afterByPath := map[pathKey]Folder{}
+	for _, folder := range after {
+		afterByPath[pathKey{folder.OrgId, folder.Paths}] = folder
+	}
+	beforeByPath := map[pathKey]Folder{}
+	for _, folder := range before {
+		beforeByPath[pathKey{folder.OrgId, folder.Paths}] = folder
+	}
+
+	// Folders after each folder before turned into, matching parents before their children
+	matches := map[pathKey]Folder{}
+	matched := map[pathKey]bool{}
+	match := func(b Folder, a Folder) {
+		matches[pathKey{b.OrgId, b.Paths}] = a
+		matched[pathKey{a.OrgId, a.Paths}] = true
+	}
+	byDepth := append([]Folder{}, before...)
+	sort.SliceStable(byDepth, func(i, j int) bool {
+		return strings.Count(byDepth[i].Paths, ".") < strings.Count(byDepth[j].Paths, ".")
+	})
+
+	// Folders left where they were, then folders with a name used once on both sides
+	for _, b := range before {
+		if a, ok := afterByPath[pathKey{b.OrgId, b.Paths}]; ok && a.Name == b.Name {
+			match(b, a)
+		}
+	}
+	beforeByKey := unmatchedByKey(before, func(b Folder) bool {
+		_, ok := matches[pathKey{b.OrgId, b.Paths}]
+		return ok
+	})
+	afterByKey := unmatchedByKey(after, func(a Folder) bool { return matched[pathKey{a.OrgId, a.Paths}] })
+	for key, b := range beforeByKey {
+		if len(b) == 1 && len(afterByKey[key]) == 1 {
+			match(b[0], afterByKey[key][0])
+		}
+	}
+	matchAcrossOrgs(before, after, matches, matched, match)
+	left := newUnmatchedChildren(before, func(b Folder) bool {
+		_, ok := matches[pathKey{b.OrgId, b.Paths}]
+		return ok
+	})
+	joined := newUnmatchedChildren(after, func(a Folder) bool { return matched[pathKey{a.OrgId, a.Paths}] })
+	for _, b := range byDepth {
+		if _, ok := matches[pathKey{b.OrgId, b.Paths}]; ok {
+			continue
+		}
+		if a, ok := renamedTo(b, left, joined, matches, matched); ok {
+			match(b, a)
+			left.remove(b)
+			joined.remove(a)
+		}
+	}
+
+	// Removed folders count the descendants removed with them, others those that came along
+	beforeCounts := countDescendants(before, beforeByPath, func(ancestor Folder, d Folder) bool {
+		a, ok := matches[pathKey{ancestor.OrgId, ancestor.Paths}]
+		m, matchedToo := matches[pathKey{d.OrgId, d.Paths}]
+		if !ok {
+			return !matchedToo
+		}
+		return matchedToo && m.OrgId == a.OrgId && strings.HasPrefix(m.Paths, a.Paths+".")
+	})
+	afterCounts := countDescendants(after, afterByPath, func(ancestor Folder, d Folder) bool { return !matched[pathKey{d.OrgId, d.Paths}] })
+
+	changes := []Change{}
+	for _, b := range before {
+		parent, hasParent := beforeByPath[pathKey{b.OrgId, parentPath(b.Paths)}]
+
+		a, ok := matches[pathKey{b.OrgId, b.Paths}]
+		descendants := beforeCounts[pathKey{b.OrgId, b.Paths}]
+		if !ok {
+			// Only the root of a removed subtree is listed
+			if _, parentMatched := matches[pathKey{parent.OrgId, parent.Paths}]; !hasParent || parentMatched {
+				changes = append(changes, Change{
+					Type: ChangeRemoved, OrgId: b.OrgId, FromOrgId: b.OrgId, Name: b.Name, From: b.Paths, Descendants: descendants,
+				})
+			}
+			continue
+		}
+
+		// Where the folder ends up if only its ancestors changed
+		expectedOrg, expectedParent := b.OrgId, ""
+		if hasParent {
+			newParent, parentMatched := matches[pathKey{parent.OrgId, parent.Paths}]
+			expectedOrg, expectedParent = newParent.OrgId, newParent.Paths
+			if !parentMatched {
+				expectedParent = "\x00"
+			}
+		}
+
+		change := Change{OrgId: a.OrgId, FromOrgId: b.OrgId, Name: a.Name, From: b.Paths, To: a.Paths, Descendants: descendants}
+		switch {
+		case a.OrgId != b.OrgId && (a.OrgId != expectedOrg || parentPath(a.Paths) != expectedParent):
+			change.Type = ChangeMovedOrg
+		case a.OrgId == b.OrgId && parentPath(a.Paths) != expectedParent:
+			change.Type = ChangeMoved
+		case a.Name != b.Name:
+			change.Type = ChangeRenamed
+		default:
+			continue
+		}
+		if a.Name != b.Name {
+			change.OldName = b.Name
+		}
+		changes = append(changes, change)
+	}
+
+	for _, a := range after {
+		if matched[pathKey{a.OrgId, a.Paths}] {
+			continue
+		}
+		// Only the root of an added subtree is listed
+		parent, hasParent := afterByPath[pathKey{a.OrgId, parentPath(a.Paths)}]
+		if hasParent && !matched[pathKey{parent.OrgId, parent.Paths}] {
+			continue
+		}
+		changes = append(changes, Change{
+			Type: ChangeAdded, OrgId: a.OrgId, Name: a.Name, To: a.Paths, Descendants: afterCounts[pathKey{a.OrgId, a.Paths}],
+		})
+	}
+
+	return changes
+}
+
+// Groups the folders not yet matched by organisation and name
+func unmatchedByKey(folders []Folder, isMatched func(folder Folder) bool) map[folderKey][]Folder {
+	res := map[folderKey][]Folder{}
+	for _, folder := range folders {
+		if !isMatched(folder) {
+			key := folderKey{folder.OrgId, folder.Name}
+			res[key] = append(res[key], folder)
+		}
+	}
+	return res
+}
+
+// Matches folders whose name left exactly one organisation and joined exactly one other
+func matchAcrossOrgs(before []Folder, after []Folder, matches map[pathKey]Folder, matched map[pathKey]bool, match func(b Folder, a Folder)) {
+	left := map[string][]Folder{}
+	for _, b := range before {
+		if _, ok := matches[pathKey{b.OrgId, b.Paths}]; !ok {
+			left[b.Name] = append(left[b.Name], b)
+		}
+	}
+	joined := map[string][]Folder{}
+	for _, a := range after {
+		if !matched[pathKey{a.OrgId, a.Paths}] {
+			joined[a.Name] = append(joined[a.Name], a)
+		}
+	}
+
+	for _, b := range before {
+		if len(left[b.Name]) == 1 && len(joined[b.Name]) == 1 && joined[b.Name][0].OrgId != b.OrgId {
+			match(b, joined[b.Name][0])
+		}
+	}
+}
+
+// Folders not matched yet grouped by organisation and parent path, with how many of each group are left
+type unmatchedChildren struct {
+	children map[pathKey][]Folder
+	count    map[pathKey]int
+}
+
+func newUnmatchedChildren(folders []Folder, isMatched func(folder Folder) bool) unmatchedChildren {
+	res := unmatchedChildren{children: map[pathKey][]Folder{}, count: map[pathKey]int{}}
+	for _, folder := range folders {
+		if !isMatched(folder) {
+			key := pathKey{folder.OrgId, parentPath(folder.Paths)}
+			res.children[key] = append(res.children[key], folder)
+			res.count[key]++
+		}
+	}
+	return res
+}
+
+// Returns the paths, relative to a folder, of the unmatched folders below it that are reached through
+// unmatched folders only, sorted
+// Folders below a matched folder go wherever it went, so they do not belong to the folder's subtree.
+func (u unmatchedChildren) below(folder Folder, isMatched func(folder Folder) bool) []string {
+	res := []string{}
+	var walk func(paths string)
+	walk = func(paths string) {
+		for _, child := range u.children[pathKey{folder.OrgId, paths}] {
+			if !isMatched(child) {
+				res = append(res, strings.TrimPrefix(child.Paths, folder.Paths+"."))
+				walk(child.Paths)
+			}
+		}
+	}
+	walk(folder.Paths)
+	sort.Strings(res)
+	return res
+}
+
+// Takes a folder that has just been matched out of the count of its group
+func (u unmatchedChildren) remove(folder Folder) {
+	u.count[pathKey{folder.OrgId, parentPath(folder.Paths)}]--
+}
+
+// Finds the folder another was renamed to, the only unmatched folder under the same parent
+// when it is also the only unmatched folder left under its old parent, and the unmatched folders below
+// each of them are at the same paths relative to it
+func renamedTo(b Folder, left unmatchedChildren, joined unmatchedChildren, matches map[pathKey]Folder, matched map[pathKey]bool) (Folder, bool) {
+	newParent := ""
+	if parent := parentPath(b.Paths); parent != "" {
+		a, ok := matches[pathKey{b.OrgId, parent}]
+		if !ok || a.OrgId != b.OrgId {
+			return Folder{}, false
+		}
+		newParent = a.Paths
+	}
+
+	key := pathKey{b.OrgId, newParent}
+	if left.count[pathKey{b.OrgId, parentPath(b.Paths)}] != 1 || joined.count[key] != 1 {
+		return Folder{}, false
+	}
+	for _, a := range joined.children[key] {
+		if matched[pathKey{a.OrgId, a.Paths}] {
+			continue
+		}
+		beforeBelow := left.below(b, func(b Folder) bool {
+			_, ok := matches[pathKey{b.OrgId, b.Paths}]
+			return ok
+		})
+		afterBelow := joined.below(a, func(a Folder) bool { return matched[pathKey{a.OrgId, a.Paths}] })
+		if !slices.Equal(beforeBelow, afterBelow) {
+			return Folder{}, false
+		}
+		return a, true
+	}
+	return Folder{}, false
+}
+
+// Counts the folders below each folder that a change to it covers
+// Every folder is counted against its ancestors, so the cost grows with the depth of the tree rather than its size.
+// Input: folders, the folders by path, whether a change to an ancestor covers a folder
+// Output: number of covered descendants by path
+func countDescendants(folders []Folder, byPath map[pathKey]Folder, covered func(ancestor Folder, folder Folder) bool) map[pathKey]int {
+	counts := map[pathKey]int{}
+	for _, folder := range folders {
+		for paths := parentPath(folder.Paths); paths != ""; paths = parentPath(paths) {
+			key := pathKey{folder.OrgId, paths}
+			if ancestor, ok := byPath[key]; ok && covered(ancestor, folder) {
+				counts[key]++
+			}
+		}
+	}
+	return counts
+}
@@ -0,0 +1,191 @@
+package folder_test
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_Diff(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	secondaryOrgID := uuid.Must(uuid.NewV4())
+
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+		{Name: "delta", Paths: "alpha.delta", OrgId: defaultOrgID},
+		{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+	}
+
+	tests := [...]struct {
+		testName string
+		after    []folder.Folder
+		want     []folder.Change
+	}{
+		{
+			testName: "unchanged",
+			after:    example1,
+			want:     []folder.Change{},
+		},
+		{
+			testName: "move subtree",
+			after: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "bravo", Paths: "golf.bravo", OrgId: defaultOrgID},
+				{Name: "charlie", Paths: "golf.bravo.charlie", OrgId: defaultOrgID},
+				{Name: "delta", Paths: "alpha.delta", OrgId: defaultOrgID},
+				{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+				{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+			},
+			want: []folder.Change{
+				{Type: folder.ChangeMoved, OrgId: defaultOrgID, FromOrgId: defaultOrgID, Name: "bravo", From: "alpha.bravo", To: "golf.bravo", Descendants: 1},
+			},
+		},
+		{
+			testName: "move inside a moved subtree",
+			after: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "bravo", Paths: "golf.bravo", OrgId: defaultOrgID},
+				{Name: "charlie", Paths: "alpha.delta.charlie", OrgId: defaultOrgID},
+				{Name: "delta", Paths: "alpha.delta", OrgId: defaultOrgID},
+				{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+				{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+			},
+			// charlie moved on its own, so it does not count towards bravo
+			want: []folder.Change{
+				{Type: folder.ChangeMoved, OrgId: defaultOrgID, FromOrgId: defaultOrgID, Name: "bravo", From: "alpha.bravo", To: "golf.bravo"},
+				{Type: folder.ChangeMoved, OrgId: defaultOrgID, FromOrgId: defaultOrgID, Name: "charlie", From: "alpha.bravo.charlie", To: "alpha.delta.charlie"},
+			},
+		},
+		{
+			testName: "rename",
+			after: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "echo", Paths: "alpha.echo", OrgId: defaultOrgID},
+				{Name: "charlie", Paths: "alpha.echo.charlie", OrgId: defaultOrgID},
+				{Name: "delta", Paths: "alpha.delta", OrgId: defaultOrgID},
+				{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+				{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+			},
+			want: []folder.Change{
+				{Type: folder.ChangeRenamed, OrgId: defaultOrgID, FromOrgId: defaultOrgID, Name: "echo", OldName: "bravo", From: "alpha.bravo", To: "alpha.echo", Descendants: 1},
+			},
+		},
+		{
+			testName: "remove and add subtrees",
+			after: []folder.Folder{
+				{Name: "delta", Paths: "delta", OrgId: defaultOrgID},
+				{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+				{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+				{Name: "hotel", Paths: "golf.hotel", OrgId: defaultOrgID},
+				{Name: "india", Paths: "golf.hotel.india", OrgId: defaultOrgID},
+			},
+			want: []folder.Change{
+				{Type: folder.ChangeRemoved, OrgId: defaultOrgID, FromOrgId: defaultOrgID, Name: "alpha", From: "alpha", Descendants: 2},
+				{Type: folder.ChangeMoved, OrgId: defaultOrgID, FromOrgId: defaultOrgID, Name: "delta", From: "alpha.delta", To: "delta"},
+				{Type: folder.ChangeAdded, OrgId: defaultOrgID, Name: "hotel", To: "golf.hotel", Descendants: 1},
+			},
+		},
+		{
+			testName: "move across organisations",
+			after: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "bravo", Paths: "foxtrot.bravo", OrgId: secondaryOrgID},
+				{Name: "charlie", Paths: "foxtrot.bravo.charlie", OrgId: secondaryOrgID},
+				{Name: "delta", Paths: "alpha.delta", OrgId: defaultOrgID},
+				{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+				{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+			},
+			want: []folder.Change{
+				{Type: folder.ChangeMovedOrg, OrgId: secondaryOrgID, FromOrgId: defaultOrgID, Name: "bravo", From: "alpha.bravo", To: "foxtrot.bravo", Descendants: 1},
+			},
+		},
+		{
+			testName: "replacing a folder with two is not a rename",
+			after: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+				{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+				{Name: "delta", Paths: "alpha.delta", OrgId: defaultOrgID},
+				{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+				{Name: "hotel", Paths: "hotel", OrgId: defaultOrgID},
+				{Name: "india", Paths: "india", OrgId: defaultOrgID},
+			},
+			want: []folder.Change{
+				{Type: folder.ChangeRemoved, OrgId: defaultOrgID, FromOrgId: defaultOrgID, Name: "golf", From: "golf"},
+				{Type: folder.ChangeAdded, OrgId: defaultOrgID, Name: "hotel", To: "hotel"},
+				{Type: folder.ChangeAdded, OrgId: defaultOrgID, Name: "india", To: "india"},
+			},
+		},
+		{
+			testName: "replacing a folder with an empty one is not a rename",
+			after: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "echo", Paths: "alpha.echo", OrgId: defaultOrgID},
+				{Name: "delta", Paths: "alpha.delta", OrgId: defaultOrgID},
+				{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+				{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+			},
+			want: []folder.Change{
+				{Type: folder.ChangeRemoved, OrgId: defaultOrgID, FromOrgId: defaultOrgID, Name: "bravo", From: "alpha.bravo", Descendants: 1},
+				{Type: folder.ChangeAdded, OrgId: defaultOrgID, Name: "echo", To: "alpha.echo"},
+			},
+		},
+		{
+			testName: "rename with unmatched descendants at the same paths",
+			after: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "echo", Paths: "alpha.echo", OrgId: defaultOrgID},
+				{Name: "charlie", Paths: "alpha.echo.charlie", OrgId: defaultOrgID},
+				{Name: "delta", Paths: "alpha.delta", OrgId: defaultOrgID},
+				{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+				{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+				{Name: "charlie", Paths: "golf.charlie", OrgId: defaultOrgID},
+			},
+			// charlie is used twice after, so it is only matched once bravo's rename lines it up
+			want: []folder.Change{
+				{Type: folder.ChangeRenamed, OrgId: defaultOrgID, FromOrgId: defaultOrgID, Name: "echo", OldName: "bravo", From: "alpha.bravo", To: "alpha.echo", Descendants: 1},
+				{Type: folder.ChangeAdded, OrgId: defaultOrgID, Name: "charlie", To: "golf.charlie"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			assert.Equal(t, tt.want, folder.Diff(example1, tt.after))
+		})
+	}
+}
+
+func Test_folder_Diff_MoveFolder(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	before := folder.GetSampleData()
+	f := folder.NewDriver(append([]folder.Folder{}, before...))
+
+	var root, dst folder.Folder
+	for _, folder := range f.GetFoldersByOrgID(defaultOrgID) {
+		if folder.Paths == folder.Name {
+			if root.Name == "" {
+				root = folder
+			} else {
+				dst = folder
+				break
+			}
+		}
+	}
+	after, err := f.MoveFolder(root.Name, dst.Name)
+	assert.NoError(t, err)
+
+	changes := folder.Diff(before, after)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, folder.ChangeMoved, changes[0].Type)
+	assert.Equal(t, dst.Paths+"."+root.Name, changes[0].To)
+}
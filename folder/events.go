@@ -0,0 +1,190 @@
+package folder
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/gofrs/uuid"
+)
+
+type EventType string
+
+const (
+	EventCreated EventType = "created"
+	EventMoved   EventType = "moved"
+	EventRenamed EventType = "renamed"
+	EventDeleted EventType = "deleted"
+	// EventLost is sent instead of a replay when events after EventFilter.Since are no longer retained,
+	// or Since is from another driver. The subscriber should reload the folders, they reflect every event up to its Seq.
+	EventLost EventType = "lost"
+)
+
+// Event reports one folder whose path was changed by a driver mutation.
+// A mutation publishes an event for the folder it targets and for every descendant it rewrote,
+// all with the type of the mutation. OldPath is empty for created folders and NewPath for deleted ones.
+type Event struct {
+	// Seq increases by one for every event published by a driver
+	Seq     uint64    `json:"seq"`
+	Type    EventType `json:"type"`
+	OrgId   uuid.UUID `json:"org_id"`
+	Name    string    `json:"name"`
+	OldPath string    `json:"old_path,omitempty"`
+	NewPath string    `json:"new_path,omitempty"`
+}
+
+// EventFilter picks the events a subscriber receives, the zero value matches everything.
+type EventFilter struct {
+	// OrgID limits events to one organisation
+	OrgID uuid.UUID
+	// Path limits events to folders at or below a path, before or after the change
+	Path string
+	// Since replays retained events with a greater Seq before live ones, 0 replays nothing.
+	// Only the most recent events are retained, see EventLost.
+	Since uint64
+}
+
+func (filter EventFilter) Match(e Event) bool {
+	if filter.OrgID != uuid.Nil && filter.OrgID != e.OrgId {
+		return false
+	}
+	if filter.Path == "" {
+		return true
+	}
+	return inSubtree(e.OldPath, filter.Path) || inSubtree(e.NewPath, filter.Path)
+}
+
+func inSubtree(paths string, root string) bool {
+	return paths != "" && (paths == root || strings.HasPrefix(paths, root+"."))
+}
+
+const (
+	// Events buffered for each subscriber, a subscriber that falls further behind is dropped
+	subscriberBuffer = 256
+	// Recent events kept for subscribers resuming with EventFilter.Since
+	eventHistory = 1024
+)
+
+// broker fans out driver events to subscribers, the zero value is ready to use
+type broker struct {
+	mu      sync.Mutex
+	seq     uint64
+	subs    map[<-chan Event]*subscription
+	history []Event
+
+	// called synchronously after every change, e.g. to audit it
+	observers []func(origin any, m Mutation, events []Event)
+}
+
+type subscription struct {
+	ch     chan Event
+	filter EventFilter
+}
+
+// Subscribe returns a channel receiving every event matching filter from now on.
+// The channel is closed by Unsubscribe, or when the subscriber falls too far behind,
+// in which case Seq shows where events were missed.
+func (b *broker) Subscribe(filter EventFilter) <-chan Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subs == nil {
+		b.subs = map[<-chan Event]*subscription{}
+	}
+
+	replay := []Event{}
+	lost := filter.Since > b.seq || (len(b.history) > 0 && b.history[0].Seq > filter.Since+1)
+	if filter.Since > 0 && lost {
+		replay = append(replay, Event{Seq: b.seq, Type: EventLost})
+	} else if filter.Since > 0 {
+		for _, e := range b.history {
+			if e.Seq > filter.Since && filter.Match(e) {
+				replay = append(replay, e)
+			}
+		}
+	}
+
+	ch := make(chan Event, subscriberBuffer+len(replay))
+	for _, e := range replay {
+		ch <- e
+	}
+	b.subs[ch] = &subscription{ch: ch, filter: filter}
+	return ch
+}
+
+// Unsubscribe stops and closes a channel returned by Subscribe.
+func (b *broker) Unsubscribe(ch <-chan Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if sub, ok := b.subs[ch]; ok {
+		delete(b.subs, ch)
+		close(sub.ch)
+	}
+}
+
+// Registers a function called with every change once it has been made,
+// along with the origin of the driver it was made through, see as
+func (b *broker) observe(fn func(origin any, m Mutation, events []Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.observers = append(b.observers, fn)
+}
+
+// Numbers the events of a change, delivers them without blocking the driver and then calls the observers
+func (b *broker) publish(origin any, m Mutation, events []Event) {
+	b.mu.Lock()
+	for i := range events {
+		b.seq++
+		events[i].Seq = b.seq
+		if len(b.history) == eventHistory {
+			b.history = append(b.history[:0], b.history[1:]...)
+		}
+		b.history = append(b.history, events[i])
+
+		for key, sub := range b.subs {
+			if !sub.filter.Match(events[i]) {
+				continue
+			}
+			select {
+			case sub.ch <- events[i]:
+			default:
+				delete(b.subs, key)
+				close(sub.ch)
+			}
+		}
+	}
+	observers := b.observers
+	b.mu.Unlock()
+
+	for _, fn := range observers {
+		fn(origin, m, events)
+	}
+}
+
+// Works out the events a mutation causes from the folders before it is applied
+// Input: folders before the change, mutation
+// Output: one event per folder whose path changes
+func mutationEvents(folders []Folder, m Mutation) []Event {
+	if m.Op == OpCreate {
+		return []Event{{Type: EventCreated, OrgId: m.OrgId, Name: m.Name, NewPath: m.To}}
+	}
+
+	types := map[Op]EventType{OpMove: EventMoved, OpRename: EventRenamed, OpDelete: EventDeleted}
+	events := []Event{}
+	for _, folder := range folders {
+		if folder.OrgId != m.OrgId || !inSubtree(folder.Paths, m.From) {
+			continue
+		}
+
+		e := Event{Type: types[m.Op], OrgId: folder.OrgId, Name: folder.Name, OldPath: folder.Paths}
+		if m.Op != OpDelete {
+			e.NewPath = m.To + strings.TrimPrefix(folder.Paths, m.From)
+		}
+		if folder.Paths == m.From {
+			e.Name = m.Name
+		}
+		events = append(events, e)
+	}
+	return events
+}
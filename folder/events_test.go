@@ -0,0 +1,204 @@
+package folder_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// Reads every event already published to a channel
+func drain(ch <-chan folder.Event) []folder.Event {
+	events := []folder.Event{}
+	for {
+		select {
+		case e := <-ch:
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
+func Test_folder_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	secondaryOrgID := uuid.Must(uuid.NewV4())
+
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+		{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+	}
+
+	tests := [...]struct {
+		testName string
+		filter   folder.EventFilter
+		change   func(f folder.IDriver) error
+		want     []folder.Event
+	}{
+		{
+			testName: "Move reports descendants",
+			change: func(f folder.IDriver) error {
+				_, err := f.MoveFolder("bravo", "golf")
+				return err
+			},
+			want: []folder.Event{
+				{Seq: 1, Type: folder.EventMoved, OrgId: defaultOrgID, Name: "bravo", OldPath: "alpha.bravo", NewPath: "golf.bravo"},
+				{Seq: 2, Type: folder.EventMoved, OrgId: defaultOrgID, Name: "charlie", OldPath: "alpha.bravo.charlie", NewPath: "golf.bravo.charlie"},
+			},
+		},
+		{
+			testName: "Rename",
+			change: func(f folder.IDriver) error {
+				_, err := f.RenameFolder("bravo", "beta")
+				return err
+			},
+			want: []folder.Event{
+				{Seq: 1, Type: folder.EventRenamed, OrgId: defaultOrgID, Name: "beta", OldPath: "alpha.bravo", NewPath: "alpha.beta"},
+				{Seq: 2, Type: folder.EventRenamed, OrgId: defaultOrgID, Name: "charlie", OldPath: "alpha.bravo.charlie", NewPath: "alpha.beta.charlie"},
+			},
+		},
+		{
+			testName: "Delete",
+			change: func(f folder.IDriver) error {
+				_, err := f.DeleteFolder("alpha")
+				return err
+			},
+			want: []folder.Event{
+				{Seq: 1, Type: folder.EventDeleted, OrgId: defaultOrgID, Name: "alpha", OldPath: "alpha"},
+				{Seq: 2, Type: folder.EventDeleted, OrgId: defaultOrgID, Name: "bravo", OldPath: "alpha.bravo"},
+				{Seq: 3, Type: folder.EventDeleted, OrgId: defaultOrgID, Name: "charlie", OldPath: "alpha.bravo.charlie"},
+			},
+		},
+		{
+			testName: "Create",
+			change: func(f folder.IDriver) error {
+				_, err := f.CreateFolder(secondaryOrgID, "hotel", "foxtrot")
+				return err
+			},
+			want: []folder.Event{
+				{Seq: 1, Type: folder.EventCreated, OrgId: secondaryOrgID, Name: "hotel", NewPath: "foxtrot.hotel"},
+			},
+		},
+		{
+			testName: "Filter by subtree sees folders moving in",
+			filter:   folder.EventFilter{Path: "golf"},
+			change: func(f folder.IDriver) error {
+				if _, err := f.CreateFolder(defaultOrgID, "hotel", "alpha"); err != nil {
+					return err
+				}
+				_, err := f.MoveFolder("charlie", "golf")
+				return err
+			},
+			want: []folder.Event{
+				{Seq: 2, Type: folder.EventMoved, OrgId: defaultOrgID, Name: "charlie", OldPath: "alpha.bravo.charlie", NewPath: "golf.charlie"},
+			},
+		},
+		{
+			testName: "Filter by org",
+			filter:   folder.EventFilter{OrgID: secondaryOrgID},
+			change: func(f folder.IDriver) error {
+				if _, err := f.DeleteFolder("golf"); err != nil {
+					return err
+				}
+				_, err := f.RenameFolder("foxtrot", "fox")
+				return err
+			},
+			want: []folder.Event{
+				{Seq: 2, Type: folder.EventRenamed, OrgId: secondaryOrgID, Name: "fox", OldPath: "foxtrot", NewPath: "fox"},
+			},
+		},
+		{
+			testName: "Failed change publishes nothing",
+			change: func(f folder.IDriver) error {
+				if _, err := f.MoveFolder("alpha", "charlie"); err == nil {
+					return errors.New("move into a child succeeded")
+				}
+				return nil
+			},
+			want: []folder.Event{},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, d := range testDrivers {
+			t.Run(d.name+"/"+tt.testName, func(t *testing.T) {
+				f := d.new(t, example1)
+				ch := f.Subscribe(tt.filter)
+				assert.NoError(t, tt.change(f))
+				assert.Equal(t, tt.want, drain(ch))
+			})
+		}
+	}
+}
+
+func Test_folder_Unsubscribe(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	f := folder.NewDriver([]folder.Folder{})
+
+	ch := f.Subscribe(folder.EventFilter{})
+	f.Unsubscribe(ch)
+	_, ok := <-ch
+	assert.False(t, ok)
+
+	// A subscriber that never reads is dropped rather than blocking the driver
+	slow := f.Subscribe(folder.EventFilter{})
+	for i := range 300 {
+		_, err := f.CreateFolder(defaultOrgID, fmt.Sprintf("folder%d", i), "")
+		assert.NoError(t, err)
+	}
+	events := []folder.Event{}
+	for e := range slow {
+		events = append(events, e)
+	}
+	assert.Len(t, events, 256)
+}
+
+func Test_folder_Subscribe_Since(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	f := folder.NewDriver([]folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+	})
+	for _, name := range []string{"bravo", "charlie", "delta"} {
+		_, err := f.CreateFolder(defaultOrgID, name, "alpha")
+		assert.NoError(t, err)
+	}
+
+	t.Run("Replay after a sequence number", func(t *testing.T) {
+		ch := f.Subscribe(folder.EventFilter{Since: 1})
+		defer f.Unsubscribe(ch)
+		assert.Equal(t, []folder.Event{
+			{Seq: 2, Type: folder.EventCreated, OrgId: defaultOrgID, Name: "charlie", NewPath: "alpha.charlie"},
+			{Seq: 3, Type: folder.EventCreated, OrgId: defaultOrgID, Name: "delta", NewPath: "alpha.delta"},
+		}, drain(ch))
+	})
+
+	t.Run("Sequence number from another driver", func(t *testing.T) {
+		ch := f.Subscribe(folder.EventFilter{Since: 10})
+		defer f.Unsubscribe(ch)
+		assert.Equal(t, []folder.Event{{Seq: 3, Type: folder.EventLost}}, drain(ch))
+	})
+
+	t.Run("Events no longer retained", func(t *testing.T) {
+		g := folder.NewDriver([]folder.Folder{})
+		for i := range 1100 {
+			_, err := g.CreateFolder(defaultOrgID, fmt.Sprintf("folder%d", i), "")
+			assert.NoError(t, err)
+		}
+		ch := g.Subscribe(folder.EventFilter{Since: 5})
+		defer g.Unsubscribe(ch)
+		assert.Equal(t, []folder.Event{{Seq: 1100, Type: folder.EventLost}}, drain(ch))
+	})
+}
@@ -1,6 +1,23 @@
 package folder
 
-import "github.com/gofrs/uuid"
+import (
+	"errors"
+	"sync"
+
+	"github.com/gofrs/uuid"
+)
+
+// Errors returned by the drivers, wrapped with the detail of what went wrong so they can be matched with errors.Is.
+var (
+	// ErrFolderNotFound is returned when a named folder, parent or destination does not exist.
+	ErrFolderNotFound = errors.New("folder does not exist")
+	// ErrFolderExists is returned when a change would give an organisation two folders with the same name.
+	ErrFolderExists = errors.New("folder already exists")
+	// ErrInvalidMove is returned when a folder cannot be moved to the destination.
+	ErrInvalidMove = errors.New("cannot move a folder")
+	// ErrInvalidName is returned when a name cannot be used as a folder path label.
+	ErrInvalidName = errors.New("invalid folder name")
+)
 
 type IDriver interface {
 	// GetFoldersByOrgID returns all folders that belong to a specific orgID.
@@ -9,25 +26,133 @@ type IDriver interface {
 	// Implement the following methods:
 	// GetAllChildFolders returns all child folders of a specific folder.
 	GetAllChildFolders(orgID uuid.UUID, name string) ([]Folder, error)
+	// GetChildFoldersToDepth returns the child folders up to depth levels below a specific folder, every level when depth is not positive.
+	GetChildFoldersToDepth(orgID uuid.UUID, name string, depth int) ([]Folder, error)
+	// GetAncestorFolders returns the ancestors of a specific folder, starting from the root.
+	GetAncestorFolders(orgID uuid.UUID, name string) ([]Folder, error)
 
 	// component 2
 	// Implement the following methods:
 	// MoveFolder moves a folder to a new destination.
 	MoveFolder(name string, dst string) ([]Folder, error)
+	// RenameFolder renames a folder, updating the paths of its children.
+	RenameFolder(name string, newName string) ([]Folder, error)
+	// DeleteFolder deletes a folder and all of its children.
+	DeleteFolder(name string) ([]Folder, error)
+	// MoveFolderInOrg moves a folder to a new destination, both looked up by name within an organisation.
+	MoveFolderInOrg(orgID uuid.UUID, name string, dst string) ([]Folder, error)
+	// RenameFolderInOrg renames a folder looked up by name within an organisation.
+	RenameFolderInOrg(orgID uuid.UUID, name string, newName string) ([]Folder, error)
+	// DeleteFolderInOrg deletes a folder looked up by name within an organisation, and all of its children.
+	DeleteFolderInOrg(orgID uuid.UUID, name string) ([]Folder, error)
+	// CreateFolder creates a folder inside parent, or at the root when parent is empty.
+	CreateFolder(orgID uuid.UUID, name string, parent string) ([]Folder, error)
+
+	// Subscribe returns a channel of change events matching the filter.
+	Subscribe(filter EventFilter) <-chan Event
+	// Unsubscribe stops and closes a channel returned by Subscribe.
+	Unsubscribe(ch <-chan Event)
 }
 
+// driver is safe for concurrent use, every method takes the lock and hands back copies of its folders
 type driver struct {
+	*driverState
+
+	// passed to the observers of changes made through this driver, see as
+	origin any
+}
+
+// State shared by a driver and the views of it returned by as
+type driverState struct {
+	// held for reading by queries and for writing by changes, including publishing their events
+	mu sync.RWMutex
+
 	// define attributes here
 	// data structure to store folders
 	// or preprocessed data
 
 	// example: feel free to change the data structure, if slice is not what you want
 	folders []Folder
+
+	// optional backing store, every change is applied to it before the in-memory folders
+	store Store
+
+	// subscribers to change events
+	broker
 }
 
 func NewDriver(folders []Folder) IDriver {
-	return &driver{
-		// initialize attributes here
-		folders: folders,
+	// initialize attributes here
+	return newDriver(folders, nil)
+}
+
+func newDriver(folders []Folder, store Store) *driver {
+	return &driver{driverState: &driverState{folders: folders, store: store}}
+}
+
+// Creates a driver over the folders held by a store and keeps the store up to date with every change
+// Input: store
+// Output: driver, error
+// Errors: Errors from loading the store
+func NewDriverWithStore(store Store) (IDriver, error) {
+	folders, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return newDriver(folders, store), nil
+}
+
+// Returns a view of the driver sharing its folders, whose changes reach the observers with origin
+func (f *driver) as(origin any) IDriver {
+	return &driver{driverState: f.driverState, origin: origin}
+}
+
+// Numbers and delivers the events of a change made through this driver
+func (f *driver) publish(m Mutation, events []Event) {
+	f.broker.publish(f.origin, m, events)
+}
+
+// Records a change in the backing store if the driver has one
+// Called before the in-memory folders change, the events are published once they have.
+// Input: mutation
+// Output: events caused by the change, error
+// Errors: Errors from the store
+func (f *driver) persist(m Mutation) ([]Event, error) {
+	if f.store != nil {
+		if err := f.store.Apply(m); err != nil {
+			return nil, err
+		}
 	}
+	return mutationEvents(f.folders, m), nil
+}
+
+// Load returns a copy of every folder held by the driver.
+func (f *driver) Load() ([]Folder, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return append([]Folder{}, f.folders...), nil
+}
+
+// Applies a mutation directly, without the checks made by the named methods, e.g. to undo a change
+// Input: mutation
+// Output: error
+// Errors: Unknown operation, missing folder, folder already exists, errors from the store
+func (f *driver) Apply(m Mutation) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	folders, err := applyMutation(f.folders, m)
+	if err != nil {
+		return err
+	}
+	events, err := f.persist(m)
+	if err != nil {
+		return err
+	}
+
+	f.folders = folders
+	f.publish(m, events)
+	return nil
 }
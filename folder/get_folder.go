@@ -1,10 +1,11 @@
 package folder
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/gofrs/uuid"
 
+	"sort"
 	"strings"
 )
 
@@ -13,6 +14,14 @@ func GetAllFolders() []Folder {
 }
 
 func (f *driver) GetFoldersByOrgID(orgID uuid.UUID) []Folder {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.foldersByOrgID(orgID)
+}
+
+// Returns the folders of an organisation, the caller holds the lock
+func (f *driver) foldersByOrgID(orgID uuid.UUID) []Folder {
 	folders := f.folders
 
 	res := []Folder{}
@@ -34,7 +43,19 @@ func (f *driver) GetFoldersByOrgID(orgID uuid.UUID) []Folder {
 // Output: slice of child folders, IO errors
 // Errors: Invalid folder
 func (f *driver) GetAllChildFolders(orgID uuid.UUID, name string) ([]Folder, error) {
-	folders := f.GetFoldersByOrgID(orgID)
+	return f.GetChildFoldersToDepth(orgID, name, 0)
+}
+
+// Retrieves the children of a folder up to a number of levels below it
+// Uses the same first-match rule for duplicate names as GetAllChildFolders
+// Input: organisation ID, folder name, depth, every level when it is not positive
+// Output: slice of child folders, IO errors
+// Errors: Invalid folder
+func (f *driver) GetChildFoldersToDepth(orgID uuid.UUID, name string, depth int) ([]Folder, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	folders := f.foldersByOrgID(orgID)
 
 	// Find the desired folder
 	var path string
@@ -47,16 +68,56 @@ func (f *driver) GetAllChildFolders(orgID uuid.UUID, name string) ([]Folder, err
 
 	// Not found case: return nil if folder is not found
 	if path == "" {
-		return nil, errors.New("folder does not exist in the specified organisation")
+		return nil, fmt.Errorf("%w in the specified organisation", ErrFolderNotFound)
 	}
 
 	// Find all children and append them to a result slice
+	level := strings.Count(path, ".")
 	children := []Folder{}
 	for _, folder := range folders {
 		// Check if current folder has the parent path as a prefix of their own path
-		if strings.HasPrefix(folder.Paths, path+".") {
+		if strings.HasPrefix(folder.Paths, path+".") && (depth <= 0 || strings.Count(folder.Paths, ".")-level <= depth) {
 			children = append(children, folder)
 		}
 	}
 	return children, nil
 }
+
+// Retrieves the ancestors of a folder specified by organisation ID and name, ordered from the root down
+// Uses the same first-match rule for duplicate names as GetAllChildFolders
+// Input: organisation ID, folder name
+// Output: slice of ancestor folders, IO errors
+// Errors: Invalid folder
+func (f *driver) GetAncestorFolders(orgID uuid.UUID, name string) ([]Folder, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	folders := f.foldersByOrgID(orgID)
+
+	// Find the desired folder
+	var path string
+	for _, folder := range folders {
+		if folder.Name == name {
+			path = folder.Paths
+			break
+		}
+	}
+
+	if path == "" {
+		return nil, fmt.Errorf("%w in the specified organisation", ErrFolderNotFound)
+	}
+
+	// Ancestors are the folders whose path is a prefix of the folder's path
+	ancestors := []Folder{}
+	for _, folder := range folders {
+		if strings.HasPrefix(path, folder.Paths+".") {
+			ancestors = append(ancestors, folder)
+		}
+	}
+
+	// Shorter paths sit closer to the root
+	sort.SliceStable(ancestors, func(i, j int) bool {
+		return len(ancestors[i].Paths) < len(ancestors[j].Paths)
+	})
+	return ancestors, nil
+}
@@ -89,11 +89,13 @@ func Test_folder_GetFoldersByOrgID(t *testing.T) {
 		},
 	}
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			f := folder.NewDriver(tt.folders)
-			get := f.GetFoldersByOrgID(tt.orgID)
-			assert.Equal(t, tt.want, get)
-		})
+		for _, d := range testDrivers {
+			t.Run(d.name+"/"+tt.name, func(t *testing.T) {
+				f := d.new(t, tt.folders)
+				get := f.GetFoldersByOrgID(tt.orgID)
+				assert.Equal(t, tt.want, get)
+			})
+		}
 	}
 }
 
@@ -208,12 +210,14 @@ func Test_folder_GetAllChildFolders(t *testing.T) {
 		},
 	}
 	for _, tt := range tests {
-		t.Run(tt.testName, func(t *testing.T) {
-			f := folder.NewDriver(tt.folders)
-			get, err := f.GetAllChildFolders(tt.orgID, tt.parent)
-			assert.Equal(t, tt.want, get)
-			assert.ErrorIs(t, err, nil)
-		})
+		for _, d := range testDrivers {
+			t.Run(d.name+"/"+tt.testName, func(t *testing.T) {
+				f := d.new(t, tt.folders)
+				get, err := f.GetAllChildFolders(tt.orgID, tt.parent)
+				assert.Equal(t, tt.want, get)
+				assert.ErrorIs(t, err, nil)
+			})
+		}
 	}
 }
 
@@ -262,10 +266,78 @@ func Test_folder_GetAllChildFolders_Error(t *testing.T) {
 		},
 	}
 	for _, tt := range tests {
-		t.Run(tt.testName, func(t *testing.T) {
-			f := folder.NewDriver(tt.folders)
-			_, err := f.GetAllChildFolders(tt.orgID, tt.parent)
-			assert.ErrorContains(t, err, tt.want)
-		})
+		for _, d := range testDrivers {
+			t.Run(d.name+"/"+tt.testName, func(t *testing.T) {
+				f := d.new(t, tt.folders)
+				_, err := f.GetAllChildFolders(tt.orgID, tt.parent)
+				assert.ErrorContains(t, err, tt.want)
+			})
+		}
+	}
+}
+
+func Test_folder_GetChildFoldersToDepth(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+		{Name: "kilo", Paths: "alpha.bravo.charlie.kilo", OrgId: defaultOrgID},
+		{Name: "delta", Paths: "alpha.delta", OrgId: defaultOrgID},
+	}
+
+	tests := [...]struct {
+		testName string
+		parent   string
+		depth    int
+		want     []folder.Folder
+		err      string
+	}{
+		{
+			testName: "One level",
+			parent:   "alpha",
+			depth:    1,
+			want: []folder.Folder{
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+				{Name: "delta", Paths: "alpha.delta", OrgId: defaultOrgID},
+			},
+		},
+		{
+			testName: "Two levels from an inner folder",
+			parent:   "bravo",
+			depth:    2,
+			want: []folder.Folder{
+				{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+				{Name: "kilo", Paths: "alpha.bravo.charlie.kilo", OrgId: defaultOrgID},
+			},
+		},
+		{
+			testName: "Every level",
+			parent:   "alpha",
+			want:     example1[1:],
+		},
+		{
+			testName: "Missing folder",
+			parent:   "zulu",
+			depth:    1,
+			err:      "folder does not exist in the specified organisation",
+		},
+	}
+
+	for _, tt := range tests {
+		for _, d := range testDrivers {
+			t.Run(d.name+"/"+tt.testName, func(t *testing.T) {
+				f := d.new(t, example1)
+				get, err := f.GetChildFoldersToDepth(defaultOrgID, tt.parent, tt.depth)
+				if tt.err != "" {
+					assert.EqualError(t, err, tt.err)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, get)
+			})
+		}
 	}
 }
@@ -0,0 +1,180 @@
+package folder
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+type GraphOptions struct {
+	// OrgID limits the graph to one organisation, uuid.Nil draws every organisation
+	OrgID uuid.UUID
+	// Root limits the graph to the subtree of the first folder with this name
+	Root string
+	// Before highlights folders Diff finds moved since this folder set, e.g. after MoveFolder,
+	// so renaming a folder does not highlight its children
+	Before []Folder
+}
+
+// A folder in the graph with its generated node ID
+type graphNode struct {
+	id     string
+	folder Folder
+	moved  bool
+}
+
+type graphEdge struct {
+	from, to string
+}
+
+// One cluster per organisation
+type graphCluster struct {
+	orgID uuid.UUID
+	nodes []graphNode
+	edges []graphEdge
+}
+
+// Filters folders by the graph options and lays them out as clusters
+// Input: folders, graph options
+// Output: clusters, error
+// Errors: Root folder does not exist, duplicate folders
+func buildGraph(folders []Folder, opts GraphOptions) ([]graphCluster, error) {
+	// Only the folder a move was made to is highlighted, not the descendants that came along
+	moved := map[pathKey]bool{}
+	if opts.Before != nil {
+		for _, change := range Diff(opts.Before, folders) {
+			if change.Type == ChangeMoved || change.Type == ChangeMovedOrg {
+				moved[pathKey{change.OrgId, change.To}] = true
+			}
+		}
+	}
+
+	if opts.OrgID != uuid.Nil {
+		folders = NewDriver(folders).GetFoldersByOrgID(opts.OrgID)
+	}
+
+	if opts.Root != "" {
+		var root *Folder
+		for i := range folders {
+			if folders[i].Name == opts.Root {
+				root = &folders[i]
+				break
+			}
+		}
+		if root == nil {
+			return nil, fmt.Errorf("%w in the specified organisation", ErrFolderNotFound)
+		}
+
+		subtree := []Folder{*root}
+		for _, folder := range folders {
+			if folder.OrgId == root.OrgId && strings.HasPrefix(folder.Paths, root.Paths+".") {
+				subtree = append(subtree, folder)
+			}
+		}
+		folders = subtree
+	}
+
+	trees, err := buildTrees(folders)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := []graphCluster{}
+	next := 0
+	for _, tree := range trees {
+		cluster := graphCluster{orgID: tree.OrgId}
+
+		var walk func(nodes []*treeNode, parentID string)
+		walk = func(nodes []*treeNode, parentID string) {
+			for _, node := range nodes {
+				id := fmt.Sprintf("n%d", next)
+				next++
+
+				cluster.nodes = append(cluster.nodes, graphNode{id: id, folder: node.Folder, moved: moved[pathKey{node.OrgId, node.Paths}]})
+				if parentID != "" {
+					cluster.edges = append(cluster.edges, graphEdge{from: parentID, to: id})
+				}
+				walk(node.Children, id)
+			}
+		}
+		walk(tree.Roots, "")
+
+		clusters = append(clusters, cluster)
+	}
+	return clusters, nil
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Writes folders as a Graphviz DOT digraph with one cluster per organisation
+// Input: writer, folders, graph options
+// Output: error
+// Errors: Root folder does not exist, duplicate folders, IO errors
+func ExportDOT(w io.Writer, folders []Folder, opts GraphOptions) error {
+	clusters, err := buildGraph(folders, opts)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph folders {")
+	fmt.Fprintln(bw, "\tnode [shape=folder];")
+	for i, cluster := range clusters {
+		fmt.Fprintf(bw, "\tsubgraph cluster_%d {\n", i)
+		fmt.Fprintf(bw, "\t\tlabel=\"%s\";\n", cluster.orgID)
+		for _, node := range cluster.nodes {
+			style := ""
+			if node.moved {
+				style = `, style=filled, fillcolor="#ffd966"`
+			}
+			fmt.Fprintf(bw, "\t\t%s [label=\"%s\"%s];\n", node.id, dotEscaper.Replace(node.folder.Name), style)
+		}
+		for _, edge := range cluster.edges {
+			fmt.Fprintf(bw, "\t\t%s -> %s;\n", edge.from, edge.to)
+		}
+		fmt.Fprintln(bw, "\t}")
+	}
+	fmt.Fprintln(bw, "}")
+
+	return bw.Flush()
+}
+
+// Mermaid labels use HTML entities for characters that would end the label
+var mermaidEscaper = strings.NewReplacer(`"`, "#quot;", "\n", " ")
+
+// Writes folders as a Mermaid "graph TD" flowchart with one subgraph per organisation
+// Input: writer, folders, graph options
+// Output: error
+// Errors: Root folder does not exist, duplicate folders, IO errors
+func ExportMermaid(w io.Writer, folders []Folder, opts GraphOptions) error {
+	clusters, err := buildGraph(folders, opts)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "graph TD")
+	moved := []string{}
+	for i, cluster := range clusters {
+		fmt.Fprintf(bw, "\tsubgraph org%d[\"%s\"]\n", i, cluster.orgID)
+		for _, node := range cluster.nodes {
+			fmt.Fprintf(bw, "\t\t%s[\"%s\"]\n", node.id, mermaidEscaper.Replace(node.folder.Name))
+			if node.moved {
+				moved = append(moved, node.id)
+			}
+		}
+		for _, edge := range cluster.edges {
+			fmt.Fprintf(bw, "\t\t%s --> %s\n", edge.from, edge.to)
+		}
+		fmt.Fprintln(bw, "\tend")
+	}
+	if len(moved) > 0 {
+		fmt.Fprintln(bw, "\tclassDef moved fill:#ffd966,stroke:#b45f06")
+		fmt.Fprintf(bw, "\tclass %s moved\n", strings.Join(moved, ","))
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,139 @@
+package folder_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_ExportDOT(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	secondaryOrgID := uuid.FromStringOrNil("38b9879b-f73b-4b0e-b9d9-4fc4c23643a7")
+
+	before := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+		{Name: `say "hi"`, Paths: `say "hi"`, OrgId: secondaryOrgID},
+	}
+	after, err := folder.NewDriver(append([]folder.Folder{}, before...)).MoveFolder("bravo", "golf")
+	assert.NoError(t, err)
+
+	var b strings.Builder
+	err = folder.ExportDOT(&b, after, folder.GraphOptions{Before: before})
+	assert.NoError(t, err)
+	assert.Equal(t, `digraph folders {
+	node [shape=folder];
+	subgraph cluster_0 {
+		label="c1556e17-b7c0-45a3-a6ae-9546248fb17a";
+		n0 [label="alpha"];
+		n1 [label="golf"];
+		n2 [label="bravo", style=filled, fillcolor="#ffd966"];
+		n3 [label="charlie"];
+		n1 -> n2;
+		n2 -> n3;
+	}
+	subgraph cluster_1 {
+		label="38b9879b-f73b-4b0e-b9d9-4fc4c23643a7";
+		n4 [label="say \"hi\""];
+	}
+}
+`, b.String())
+}
+
+func Test_folder_ExportMermaid(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	secondaryOrgID := uuid.FromStringOrNil("38b9879b-f73b-4b0e-b9d9-4fc4c23643a7")
+
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+		{Name: "delta", Paths: "alpha.delta", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "bravo", OrgId: secondaryOrgID},
+	}
+
+	tests := [...]struct {
+		testName string
+		opts     folder.GraphOptions
+		want     string
+		err      string
+	}{
+		{
+			testName: "Subtree of one organisation",
+			opts:     folder.GraphOptions{OrgID: defaultOrgID, Root: "bravo"},
+			want: `graph TD
+	subgraph org0["c1556e17-b7c0-45a3-a6ae-9546248fb17a"]
+		n0["bravo"]
+		n1["charlie"]
+		n0 --> n1
+	end
+`,
+		},
+		{
+			testName: "Highlight moved folder",
+			opts: folder.GraphOptions{OrgID: defaultOrgID, Before: []folder.Folder{
+				{Name: "delta", Paths: "alpha.bravo.delta", OrgId: defaultOrgID},
+			}},
+			want: `graph TD
+	subgraph org0["c1556e17-b7c0-45a3-a6ae-9546248fb17a"]
+		n0["alpha"]
+		n1["bravo"]
+		n2["charlie"]
+		n3["delta"]
+		n0 --> n1
+		n1 --> n2
+		n0 --> n3
+	end
+	classDef moved fill:#ffd966,stroke:#b45f06
+	class n3 moved
+`,
+		},
+		{
+			testName: "Renamed parent does not highlight its children",
+			opts: folder.GraphOptions{OrgID: defaultOrgID, Before: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "echo", Paths: "alpha.echo", OrgId: defaultOrgID},
+				{Name: "charlie", Paths: "alpha.echo.charlie", OrgId: defaultOrgID},
+				{Name: "delta", Paths: "alpha.delta", OrgId: defaultOrgID},
+			}},
+			want: `graph TD
+	subgraph org0["c1556e17-b7c0-45a3-a6ae-9546248fb17a"]
+		n0["alpha"]
+		n1["bravo"]
+		n2["charlie"]
+		n3["delta"]
+		n0 --> n1
+		n1 --> n2
+		n0 --> n3
+	end
+`,
+		},
+		{
+			testName: "Root does not exist",
+			opts:     folder.GraphOptions{Root: "zulu"},
+			err:      "folder does not exist in the specified organisation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			var b strings.Builder
+			err := folder.ExportMermaid(&b, folders, tt.opts)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, b.String())
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package folder
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+// HistoryLimit bounds the changes a History keeps, the oldest are forgotten first.
+// Zero fields are unbounded.
+type HistoryLimit struct {
+	// Changes is the number of changes that can be undone
+	Changes int
+	// Mutations bounds the memory used, a delete keeps one mutation for every folder it removed
+	Mutations int
+}
+
+// History is a driver whose changes can be undone and redone.
+// Changes made to the underlying driver by anyone else are not undone, and clear the history
+// when they touch a folder it would change, so undo never rewrites someone else's work.
+// Unlike the driver, it is not safe for concurrent use.
+type History struct {
+	IDriver
+	apply func(m Mutation) error
+	limit HistoryLimit
+
+	undo []historyEntry
+	redo []historyEntry
+
+	// set while the history changes the driver, collecting what it did
+	own      bool
+	observed []Mutation
+	events   []Event
+}
+
+// A change and the mutations that reverse it
+type historyEntry struct {
+	do   []Mutation
+	undo []Mutation
+}
+
+func (e historyEntry) size() int {
+	return len(e.do) + len(e.undo)
+}
+
+// Starts keeping the history of the changes made through it
+// Input: driver from this package, limit
+// Output: history, error
+// Errors: Driver that does not publish its changes
+func NewHistory(d IDriver, limit HistoryLimit) (*History, error) {
+	driver, ok := d.(interface {
+		Apply(m Mutation) error
+		observe(fn func(origin any, m Mutation, events []Event))
+	})
+	if !ok {
+		return nil, fmt.Errorf("cannot keep the history of a %T", d)
+	}
+
+	h := &History{IDriver: d, apply: driver.Apply, limit: limit}
+	driver.observe(h.record)
+	return h, nil
+}
+
+// Called by the driver after every change
+func (h *History) record(_ any, m Mutation, events []Event) {
+	if h.own {
+		h.observed = append(h.observed, m)
+		h.events = append(h.events, events...)
+		return
+	}
+	if h.conflicts(m, events) {
+		h.undo, h.redo = nil, nil
+	}
+}
+
+// Reports whether a change made by someone else touches a folder an undo or redo would change
+func (h *History) conflicts(m Mutation, events []Event) bool {
+	for _, entries := range [][]historyEntry{h.undo, h.redo} {
+		for _, entry := range entries {
+			for _, mutations := range [][]Mutation{entry.do, entry.undo} {
+				for _, mutation := range mutations {
+					if mutation.OrgId == m.OrgId && touches(events, mutation) {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Reports whether any event is for the folder a mutation changes, or a folder above or below it
+func touches(events []Event, m Mutation) bool {
+	for _, e := range events {
+		if e.Name == m.Name ||
+			overlaps(e.OldPath, m.From) || overlaps(e.OldPath, m.To) ||
+			overlaps(e.NewPath, m.From) || overlaps(e.NewPath, m.To) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports whether one path is at or below the other
+func overlaps(a string, b string) bool {
+	return inSubtree(a, b) || inSubtree(b, a)
+}
+
+// Makes a change through the driver and records how to reverse it
+func (h *History) change(fn func() ([]Folder, error)) ([]Folder, error) {
+	h.own, h.observed, h.events = true, nil, nil
+	folders, err := fn()
+	h.own = false
+	if err != nil {
+		return nil, err
+	}
+
+	h.push(historyEntry{do: h.observed, undo: inverse(h.observed, h.events)})
+	h.redo = nil
+	return folders, nil
+}
+
+func (h *History) push(entry historyEntry) {
+	h.undo = append(h.undo, entry)
+
+	size := 0
+	for _, entry := range h.undo {
+		size += entry.size()
+	}
+	for len(h.undo) > 0 &&
+		((h.limit.Changes > 0 && len(h.undo) > h.limit.Changes) || (h.limit.Mutations > 0 && size > h.limit.Mutations)) {
+		size -= h.undo[0].size()
+		h.undo = h.undo[1:]
+	}
+}
+
+// Works out the mutations reversing a change, in the order they are applied
+// Input: mutations made by the change, events they published
+// Output: inverse mutations
+func inverse(mutations []Mutation, events []Event) []Mutation {
+	res := []Mutation{}
+	for i := len(mutations) - 1; i >= 0; i-- {
+		m := mutations[i]
+		switch m.Op {
+		case OpCreate:
+			res = append(res, Mutation{Op: OpDelete, OrgId: m.OrgId, Name: m.Name, From: m.To})
+		case OpMove, OpRename:
+			res = append(res, Mutation{Op: m.Op, OrgId: m.OrgId, Name: pathName(m.From), From: m.To, To: m.From})
+		case OpDelete:
+			// Recreate the deleted subtree, parents before their children
+			deleted := []Event{}
+			for _, e := range events {
+				if e.Type == EventDeleted && e.OrgId == m.OrgId && inSubtree(e.OldPath, m.From) {
+					deleted = append(deleted, e)
+				}
+			}
+			sort.SliceStable(deleted, func(i, j int) bool {
+				return strings.Count(deleted[i].OldPath, ".") < strings.Count(deleted[j].OldPath, ".")
+			})
+			for _, e := range deleted {
+				res = append(res, Mutation{Op: OpCreate, OrgId: e.OrgId, Name: e.Name, To: e.OldPath})
+			}
+		}
+	}
+	return res
+}
+
+// Returns the last label of a path, the name of the folder it leads to
+func pathName(paths string) string {
+	return paths[strings.LastIndex(paths, ".")+1:]
+}
+
+// Undo reverses the most recent change made through the history.
+func (h *History) Undo() error {
+	if len(h.undo) == 0 {
+		return errors.New("nothing to undo")
+	}
+	entry := h.undo[len(h.undo)-1]
+	if err := h.replay(entry.undo); err != nil {
+		return err
+	}
+
+	h.undo = h.undo[:len(h.undo)-1]
+	h.redo = append(h.redo, entry)
+	return nil
+}
+
+// Redo makes the most recently undone change again.
+func (h *History) Redo() error {
+	if len(h.redo) == 0 {
+		return errors.New("nothing to redo")
+	}
+	entry := h.redo[len(h.redo)-1]
+	if err := h.replay(entry.do); err != nil {
+		return err
+	}
+
+	h.redo = h.redo[:len(h.redo)-1]
+	h.undo = append(h.undo, entry)
+	return nil
+}
+
+// Applies recorded mutations, all of them or none, forgetting the history if they no longer apply
+func (h *History) replay(mutations []Mutation) error {
+	h.own, h.observed, h.events = true, nil, nil
+	defer func() { h.own = false }()
+
+	for _, m := range mutations {
+		if err := h.apply(m); err != nil {
+			h.undo, h.redo = nil, nil
+			err = fmt.Errorf("history no longer applies: %w", err)
+
+			// Reverse the mutations already replayed, like a patch that fails part way
+			for _, undo := range inverse(h.observed, h.events) {
+				if rollbackErr := h.apply(undo); rollbackErr != nil {
+					return fmt.Errorf("history partly replayed: %w", errors.Join(err, rollbackErr))
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// CanUndo reports whether there is a change to undo.
+func (h *History) CanUndo() bool {
+	return len(h.undo) > 0
+}
+
+// CanRedo reports whether there is an undone change to redo.
+func (h *History) CanRedo() bool {
+	return len(h.redo) > 0
+}
+
+func (h *History) MoveFolder(name string, dst string) ([]Folder, error) {
+	return h.change(func() ([]Folder, error) { return h.IDriver.MoveFolder(name, dst) })
+}
+
+func (h *History) RenameFolder(name string, newName string) ([]Folder, error) {
+	return h.change(func() ([]Folder, error) { return h.IDriver.RenameFolder(name, newName) })
+}
+
+func (h *History) DeleteFolder(name string) ([]Folder, error) {
+	return h.change(func() ([]Folder, error) { return h.IDriver.DeleteFolder(name) })
+}
+
+func (h *History) MoveFolderInOrg(orgID uuid.UUID, name string, dst string) ([]Folder, error) {
+	return h.change(func() ([]Folder, error) { return h.IDriver.MoveFolderInOrg(orgID, name, dst) })
+}
+
+func (h *History) RenameFolderInOrg(orgID uuid.UUID, name string, newName string) ([]Folder, error) {
+	return h.change(func() ([]Folder, error) { return h.IDriver.RenameFolderInOrg(orgID, name, newName) })
+}
+
+func (h *History) DeleteFolderInOrg(orgID uuid.UUID, name string) ([]Folder, error) {
+	return h.change(func() ([]Folder, error) { return h.IDriver.DeleteFolderInOrg(orgID, name) })
+}
+
+func (h *History) CreateFolder(orgID uuid.UUID, name string, parent string) ([]Folder, error) {
+	return h.change(func() ([]Folder, error) { return h.IDriver.CreateFolder(orgID, name, parent) })
+}
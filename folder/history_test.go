@@ -0,0 +1,204 @@
+package folder_test
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_History(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+		{Name: "delta", Paths: "alpha.delta", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+	}
+
+	for _, d := range testDrivers {
+		t.Run(d.name, func(t *testing.T) {
+			f := d.new(t, example1)
+			h, err := folder.NewHistory(f, folder.HistoryLimit{})
+			assert.NoError(t, err)
+
+			_, err = h.MoveFolder("golf", "delta")
+			assert.NoError(t, err)
+			_, err = h.RenameFolder("bravo", "echo")
+			assert.NoError(t, err)
+			_, err = h.DeleteFolder("echo")
+			assert.NoError(t, err)
+			_, err = h.CreateFolder(defaultOrgID, "foxtrot", "golf")
+			assert.NoError(t, err)
+			after := f.GetFoldersByOrgID(defaultOrgID)
+
+			for range 4 {
+				assert.NoError(t, h.Undo())
+			}
+			assert.ElementsMatch(t, example1, f.GetFoldersByOrgID(defaultOrgID))
+			assert.EqualError(t, h.Undo(), "nothing to undo")
+
+			for range 4 {
+				assert.NoError(t, h.Redo())
+			}
+			assert.ElementsMatch(t, after, f.GetFoldersByOrgID(defaultOrgID))
+			assert.EqualError(t, h.Redo(), "nothing to redo")
+
+			// A new change forgets what was undone
+			assert.NoError(t, h.Undo())
+			_, err = h.CreateFolder(defaultOrgID, "hotel", "")
+			assert.NoError(t, err)
+			assert.False(t, h.CanRedo())
+		})
+	}
+}
+
+func Test_folder_History_Conflicts(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+		{Name: "hotel", Paths: "hotel", OrgId: defaultOrgID},
+	}
+
+	tests := [...]struct {
+		testName string
+		change   func(f folder.IDriver) error
+		canUndo  bool
+	}{
+		{
+			testName: "unrelated change keeps history",
+			change: func(f folder.IDriver) error {
+				_, err := f.CreateFolder(defaultOrgID, "india", "hotel")
+				return err
+			},
+			canUndo: true,
+		},
+		{
+			testName: "renaming the old parent clears history",
+			change: func(f folder.IDriver) error {
+				_, err := f.RenameFolder("alpha", "able")
+				return err
+			},
+		},
+		{
+			testName: "moving the folder again clears history",
+			change: func(f folder.IDriver) error {
+				_, err := f.MoveFolder("bravo", "hotel")
+				return err
+			},
+		},
+		{
+			testName: "reusing a name clears history",
+			change: func(f folder.IDriver) error {
+				if _, err := f.DeleteFolder("golf"); err != nil {
+					return err
+				}
+				_, err := f.CreateFolder(defaultOrgID, "bravo", "hotel")
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, d := range testDrivers {
+			t.Run(d.name+"/"+tt.testName, func(t *testing.T) {
+				f := d.new(t, example1)
+				h, err := folder.NewHistory(f, folder.HistoryLimit{})
+				assert.NoError(t, err)
+
+				_, err = h.MoveFolder("bravo", "golf")
+				assert.NoError(t, err)
+				assert.NoError(t, tt.change(f))
+				assert.Equal(t, tt.canUndo, h.CanUndo())
+				if tt.canUndo {
+					assert.NoError(t, h.Undo())
+				}
+			})
+		}
+	}
+}
+
+func Test_folder_History_PartialReplay(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+	}
+
+	store := &failingStore{MemoryStore: folder.NewMemoryStore(example1), changes: 1}
+	f, err := folder.NewDriverWithStore(store)
+	assert.NoError(t, err)
+	h, err := folder.NewHistory(f, folder.HistoryLimit{})
+	assert.NoError(t, err)
+	_, err = h.DeleteFolder("bravo")
+	assert.NoError(t, err)
+	deleted := f.GetFoldersByOrgID(defaultOrgID)
+
+	// Undo recreates bravo, then fails to recreate charlie
+	store.changes = 1
+	assert.EqualError(t, h.Undo(), "history no longer applies: disk full")
+	assert.ElementsMatch(t, deleted, f.GetFoldersByOrgID(defaultOrgID))
+	stored, err := store.Load()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, deleted, stored)
+	assert.False(t, h.CanUndo())
+}
+
+func Test_folder_History_Limit(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+	}
+
+	tests := [...]struct {
+		testName string
+		limit    folder.HistoryLimit
+		undos    int
+	}{
+		{testName: "unbounded", limit: folder.HistoryLimit{}, undos: 3},
+		{testName: "changes", limit: folder.HistoryLimit{Changes: 2}, undos: 2},
+		// Undoing the delete recreates three folders, so it takes four mutations to keep
+		{testName: "mutations", limit: folder.HistoryLimit{Mutations: 4}, undos: 1},
+		{testName: "mutations with delete", limit: folder.HistoryLimit{Mutations: 6}, undos: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			h, err := folder.NewHistory(folder.NewDriver(append([]folder.Folder{}, example1...)), tt.limit)
+			assert.NoError(t, err)
+
+			_, err = h.CreateFolder(defaultOrgID, "hotel", "golf")
+			assert.NoError(t, err)
+			_, err = h.DeleteFolder("alpha")
+			assert.NoError(t, err)
+			_, err = h.MoveFolder("golf", "hotel")
+			assert.Error(t, err)
+			_, err = h.RenameFolder("hotel", "india")
+			assert.NoError(t, err)
+
+			undos := 0
+			for h.CanUndo() {
+				assert.NoError(t, h.Undo())
+				undos++
+			}
+			assert.Equal(t, tt.undos, undos)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package folder
+
+import (
+	"bufio"
+	"html/template"
+	"io"
+
+	"github.com/gofrs/uuid"
+)
+
+type HTMLOptions struct {
+	// Title defaults to "Folder report"
+	Title string
+}
+
+type htmlReport struct {
+	Title string
+	Orgs  []htmlOrg
+}
+
+type htmlOrg struct {
+	OrgID uuid.UUID
+	Count int
+	Roots []htmlNode
+}
+
+type htmlNode struct {
+	Name        string
+	Paths       string
+	Depth       int
+	Descendants int
+	Children    []htmlNode
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+#search { width: 100%; max-width: 30rem; padding: 0.4rem; font-size: 1rem; margin-bottom: 1rem; }
+.org { border-top: 1px solid #ddd; padding-top: 0.5rem; }
+.org h2 { font-family: monospace; font-size: 1rem; }
+ul { list-style: none; padding-left: 1.2rem; margin: 0; }
+summary { cursor: pointer; }
+.leaf { padding-left: 1rem; }
+.stats { color: #888; font-size: 0.8rem; margin-left: 0.5rem; }
+.match > details > summary > .name, .match > .leaf > .name { background: #ffd966; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<input id="search" type="search" placeholder="Search folders" autofocus>
+{{range .Orgs}}<section class="org">
+<h2>{{.OrgID}}<span class="stats">{{.Count}} folders</span></h2>
+<ul>{{range .Roots}}{{template "node" .}}{{end}}</ul>
+</section>
+{{else}}<p>No folders.</p>
+{{end}}<script>
+const search = document.getElementById("search");
+search.addEventListener("input", () => {
+	const query = search.value.trim();
+	const selector = '[data-name*="' + CSS.escape(query) + '" i]';
+	document.querySelectorAll("li[data-name]").forEach((li) => {
+		const match = query !== "" && li.matches(selector);
+		li.hidden = query !== "" && !match && !li.querySelector(selector);
+		li.classList.toggle("match", match);
+		const details = li.querySelector(":scope > details");
+		if (details && query !== "" && !li.hidden) {
+			details.open = true;
+		}
+	});
+});
+</script>
+</body>
+</html>
+{{define "node"}}<li data-name="{{.Name}}" title="{{.Paths}}">{{if .Children}}<details open><summary><span class="name">{{.Name}}</span>{{template "stats" .}}</summary>
+<ul>{{range .Children}}{{template "node" .}}{{end}}</ul></details>{{else}}<span class="leaf"><span class="name">{{.Name}}</span>{{template "stats" .}}</span>{{end}}</li>
+{{end}}{{define "stats"}}<span class="stats">depth {{.Depth}} · {{.Descendants}} descendants</span>{{end}}`))
+
+// Writes a self-contained HTML page with a collapsible, searchable tree per organisation
+// Each folder shows its depth and how many folders sit below it.
+// Input: writer, folders, HTML options
+// Output: error
+// Errors: Duplicate folders, IO errors
+func RenderHTML(w io.Writer, folders []Folder, opts HTMLOptions) error {
+	report := htmlReport{Title: opts.Title}
+	if report.Title == "" {
+		report.Title = "Folder report"
+	}
+
+	// Organisations in order of first appearance
+	driver := NewDriver(folders)
+	seen := map[uuid.UUID]bool{}
+	for _, folder := range folders {
+		if seen[folder.OrgId] {
+			continue
+		}
+		seen[folder.OrgId] = true
+
+		orgFolders := driver.GetFoldersByOrgID(folder.OrgId)
+		trees, err := buildTrees(orgFolders)
+		if err != nil {
+			return err
+		}
+		report.Orgs = append(report.Orgs, htmlOrg{
+			OrgID: folder.OrgId,
+			Count: len(orgFolders),
+			Roots: htmlNodes(trees[0].Roots),
+		})
+	}
+
+	bw := bufio.NewWriter(w)
+	if err := htmlReportTemplate.Execute(bw, report); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func htmlNodes(nodes []*treeNode) []htmlNode {
+	res := make([]htmlNode, 0, len(nodes))
+	for _, node := range nodes {
+		res = append(res, htmlNode{
+			Name:        node.Name,
+			Paths:       node.Paths,
+			Depth:       node.depth(),
+			Descendants: node.descendants(),
+			Children:    htmlNodes(node.Children),
+		})
+	}
+	return res
+}
@@ -0,0 +1,48 @@
+package folder_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_RenderHTML(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	secondaryOrgID := uuid.FromStringOrNil("38b9879b-f73b-4b0e-b9d9-4fc4c23643a7")
+
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "<charlie>", Paths: "alpha.bravo.<charlie>", OrgId: defaultOrgID},
+		{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+	}
+
+	var b strings.Builder
+	err := folder.RenderHTML(&b, folders, folder.HTMLOptions{Title: "Customer folders"})
+	assert.NoError(t, err)
+	get := b.String()
+
+	assert.Contains(t, get, "<title>Customer folders</title>")
+	assert.Contains(t, get, `<input id="search" type="search"`)
+	assert.Contains(t, get, "<h2>c1556e17-b7c0-45a3-a6ae-9546248fb17a<span class=\"stats\">3 folders</span></h2>")
+	assert.Contains(t, get, "<h2>38b9879b-f73b-4b0e-b9d9-4fc4c23643a7<span class=\"stats\">1 folders</span></h2>")
+	assert.Contains(t, get, `<li data-name="alpha" title="alpha"><details open><summary><span class="name">alpha</span><span class="stats">depth 1 · 2 descendants</span>`)
+	assert.Contains(t, get, `<span class="name">&lt;charlie&gt;</span><span class="stats">depth 3 · 0 descendants</span>`)
+	assert.NotContains(t, get, "<charlie>")
+	assert.Less(t, strings.Index(get, "c1556e17"), strings.Index(get, "38b9879b"))
+}
+
+func Test_folder_RenderHTML_Empty(t *testing.T) {
+	t.Parallel()
+
+	var b strings.Builder
+	err := folder.RenderHTML(&b, []folder.Folder{}, folder.HTMLOptions{})
+	assert.NoError(t, err)
+	assert.Contains(t, b.String(), "<title>Folder report</title>")
+	assert.Contains(t, b.String(), "<p>No folders.</p>")
+}
@@ -0,0 +1,282 @@
+package folder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// JournalRecord is a single line of the journal.
+type JournalRecord struct {
+	Seq uint64 `json:"seq"`
+	Mutation
+}
+
+// Journal is an append-only log of driver changes stored as newline-delimited JSON.
+// Every record is synced to disk before Append returns.
+type Journal struct {
+	mu   sync.Mutex
+	file *os.File
+	seq  uint64
+}
+
+// Opens or creates a journal file, dropping a record torn by a crash mid-write
+// New records are numbered after both the last record and floor, so an emptied journal
+// does not reuse the sequence numbers already folded into a snapshot.
+// Input: path of the journal file, last sequence number used outside the journal
+// Output: journal, error
+// Errors: IO errors, corrupted records before the end of the journal
+func OpenJournal(path string, floor uint64) (*Journal, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	j := &Journal{file: file, seq: floor}
+	end, err := j.scan(func(r JournalRecord) error {
+		j.seq = max(j.seq, r.Seq)
+		return nil
+	})
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	// Throw away anything after the last complete record
+	if err := file.Truncate(end); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.Seek(end, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return j, nil
+}
+
+// Seq returns the sequence number of the last record in the journal.
+func (j *Journal) Seq() uint64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.seq
+}
+
+// Writes a mutation to the end of the journal and syncs it to disk
+// Input: mutation
+// Output: sequence number of the new record, error
+// Errors: IO errors
+func (j *Journal) Append(m Mutation) (uint64, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	record := JournalRecord{Seq: j.seq + 1, Mutation: m}
+	b, err := json.Marshal(record)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := j.file.Write(append(b, '\n')); err != nil {
+		return 0, err
+	}
+	if err := j.file.Sync(); err != nil {
+		return 0, err
+	}
+
+	j.seq = record.Seq
+	return record.Seq, nil
+}
+
+// Calls fn for every record with a sequence number greater than after, in order
+// Input: sequence number to start after, callback
+// Output: error
+// Errors: IO errors, corrupted records, errors returned by fn
+func (j *Journal) Replay(after uint64, fn func(JournalRecord) error) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	end, err := j.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	defer j.file.Seek(end, io.SeekStart)
+
+	_, err = j.scan(func(r JournalRecord) error {
+		if r.Seq <= after {
+			return nil
+		}
+		return fn(r)
+	})
+	return err
+}
+
+// Reset empties the journal, sequence numbers keep counting up from where they were.
+func (j *Journal) Reset() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if err := j.file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := j.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return j.file.Sync()
+}
+
+func (j *Journal) Close() error {
+	return j.file.Close()
+}
+
+// Reads the journal from the start, returning the offset just past the last complete record
+func (j *Journal) scan(fn func(JournalRecord) error) (int64, error) {
+	if _, err := j.file.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	reader := bufio.NewReader(j.file)
+	var offset int64
+	for line := 1; ; line++ {
+		b, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// A record without a trailing newline was torn by a crash
+			return offset, nil
+		} else if err != nil {
+			return 0, err
+		}
+
+		record := JournalRecord{}
+		if err := json.Unmarshal(bytes.TrimSpace(b), &record); err != nil {
+			if _, peekErr := reader.Peek(1); errors.Is(peekErr, io.EOF) {
+				return offset, nil
+			}
+			return 0, fmt.Errorf("journal line %d: %w", line, err)
+		}
+		if err := fn(record); err != nil {
+			return 0, err
+		}
+		offset += int64(len(b))
+	}
+}
+
+type journalSnapshot struct {
+	Seq     uint64   `json:"seq"`
+	Folders []Folder `json:"folders"`
+}
+
+// JournalStore keeps a snapshot of the folders plus a journal of the changes made since.
+// Changes only append to the journal, Compact folds the journal back into the snapshot.
+type JournalStore struct {
+	mu           sync.Mutex
+	snapshotPath string
+	journal      *Journal
+	folders      []Folder
+}
+
+// Opens a journal store, replaying the journal over the snapshot
+// Input: path of the snapshot file, path of the journal file
+// Output: journal store, error
+// Errors: IO errors, invalid snapshot, journal records that cannot be applied
+func OpenJournalStore(snapshotPath string, journalPath string) (*JournalStore, error) {
+	snapshot := journalSnapshot{Folders: []Folder{}}
+	b, err := os.ReadFile(snapshotPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	} else if err == nil {
+		if err := json.Unmarshal(b, &snapshot); err != nil {
+			return nil, fmt.Errorf("snapshot %s: %w", snapshotPath, err)
+		}
+	}
+
+	journal, err := OpenJournal(journalPath, snapshot.Seq)
+	if err != nil {
+		return nil, err
+	}
+
+	// Records up to the snapshot sequence were already folded in by a compaction
+	folders := snapshot.Folders
+	err = journal.Replay(snapshot.Seq, func(r JournalRecord) error {
+		folders, err = applyMutation(folders, r.Mutation)
+		if err != nil {
+			return fmt.Errorf("journal record %d: %w", r.Seq, err)
+		}
+		return nil
+	})
+	if err != nil {
+		journal.Close()
+		return nil, err
+	}
+
+	return &JournalStore{
+		snapshotPath: snapshotPath,
+		journal:      journal,
+		folders:      folders,
+	}, nil
+}
+
+func (s *JournalStore) Load() ([]Folder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Folder{}, s.folders...), nil
+}
+
+// Save replaces the snapshot with the given folders and empties the journal.
+func (s *JournalStore) Save(folders []Folder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.compact(folders)
+}
+
+func (s *JournalStore) Apply(m Mutation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Validate the change before it reaches the journal
+	folders, err := applyMutation(s.folders, m)
+	if err != nil {
+		return err
+	}
+	if _, err := s.journal.Append(m); err != nil {
+		return err
+	}
+	s.folders = folders
+	return nil
+}
+
+// Compact writes the current folders to the snapshot and empties the journal.
+func (s *JournalStore) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.compact(s.folders)
+}
+
+func (s *JournalStore) Close() error {
+	return s.journal.Close()
+}
+
+func (s *JournalStore) compact(folders []Folder) error {
+	// The snapshot records the last sequence it contains, so a crash before the journal
+	// is reset does not replay the same records twice
+	b, err := json.MarshalIndent(journalSnapshot{Seq: s.journal.Seq(), Folders: folders}, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := WriteFileAtomic(s.snapshotPath, b); err != nil {
+		return err
+	}
+	if err := s.journal.Reset(); err != nil {
+		return err
+	}
+	s.folders = append([]Folder{}, folders...)
+	return nil
+}
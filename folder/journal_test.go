@@ -0,0 +1,147 @@
+package folder_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_JournalStore(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	initial := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+	}
+	moved := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "golf.bravo", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+	}
+	move := folder.Mutation{Op: folder.OpMove, OrgId: defaultOrgID, Name: "bravo", From: "alpha.bravo", To: "golf.bravo"}
+
+	tests := [...]struct {
+		testName string
+		// runs against a freshly saved store before it is reopened
+		setup func(t *testing.T, s *folder.JournalStore, journalPath string)
+		want  []folder.Folder
+	}{
+		{
+			testName: "Replay journal on open",
+			setup: func(t *testing.T, s *folder.JournalStore, journalPath string) {
+				assert.NoError(t, s.Apply(move))
+			},
+			want: moved,
+		},
+		{
+			testName: "Drop torn record",
+			setup: func(t *testing.T, s *folder.JournalStore, journalPath string) {
+				assert.NoError(t, s.Apply(move))
+				file, err := os.OpenFile(journalPath, os.O_APPEND|os.O_WRONLY, 0644)
+				assert.NoError(t, err)
+				_, err = file.WriteString(`{"seq":2,"op":"del`)
+				assert.NoError(t, err)
+				assert.NoError(t, file.Close())
+			},
+			want: moved,
+		},
+		{
+			testName: "Compact into snapshot",
+			setup: func(t *testing.T, s *folder.JournalStore, journalPath string) {
+				assert.NoError(t, s.Apply(move))
+				assert.NoError(t, s.Compact())
+				info, err := os.Stat(journalPath)
+				assert.NoError(t, err)
+				assert.Zero(t, info.Size())
+			},
+			want: moved,
+		},
+		{
+			testName: "Crash between snapshot and journal reset",
+			setup: func(t *testing.T, s *folder.JournalStore, journalPath string) {
+				assert.NoError(t, s.Apply(move))
+				journal, err := os.ReadFile(journalPath)
+				assert.NoError(t, err)
+				assert.NoError(t, s.Compact())
+				assert.NoError(t, os.WriteFile(journalPath, journal, 0644))
+			},
+			want: moved,
+		},
+		{
+			testName: "Rejected change is not journalled",
+			setup: func(t *testing.T, s *folder.JournalStore, journalPath string) {
+				err := s.Apply(folder.Mutation{Op: folder.OpDelete, OrgId: defaultOrgID, Name: "zulu", From: "zulu"})
+				assert.Error(t, err)
+			},
+			want: initial,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			dir := t.TempDir()
+			snapshotPath := filepath.Join(dir, "snapshot.json")
+			journalPath := filepath.Join(dir, "journal.ndjson")
+
+			s, err := folder.OpenJournalStore(snapshotPath, journalPath)
+			assert.NoError(t, err)
+			assert.NoError(t, s.Save(initial))
+			tt.setup(t, s, journalPath)
+			assert.NoError(t, s.Close())
+
+			reopened, err := folder.OpenJournalStore(snapshotPath, journalPath)
+			assert.NoError(t, err)
+			defer reopened.Close()
+			get, err := reopened.Load()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, get)
+		})
+	}
+
+	t.Run("Apply after reopening a compacted store", func(t *testing.T) {
+		dir := t.TempDir()
+		snapshotPath := filepath.Join(dir, "snapshot.json")
+		journalPath := filepath.Join(dir, "journal.ndjson")
+
+		s, err := folder.OpenJournalStore(snapshotPath, journalPath)
+		assert.NoError(t, err)
+		assert.NoError(t, s.Save(initial))
+		assert.NoError(t, s.Apply(move))
+		assert.NoError(t, s.Compact())
+		assert.NoError(t, s.Close())
+
+		// The journal is empty, but its next record must still come after the snapshot
+		s, err = folder.OpenJournalStore(snapshotPath, journalPath)
+		assert.NoError(t, err)
+		assert.NoError(t, s.Apply(folder.Mutation{Op: folder.OpMove, OrgId: defaultOrgID, Name: "golf", From: "golf", To: "alpha.golf"}))
+		assert.NoError(t, s.Close())
+
+		reopened, err := folder.OpenJournalStore(snapshotPath, journalPath)
+		assert.NoError(t, err)
+		defer reopened.Close()
+		get, err := reopened.Load()
+		assert.NoError(t, err)
+		assert.Equal(t, []folder.Folder{
+			{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+			{Name: "bravo", Paths: "alpha.golf.bravo", OrgId: defaultOrgID},
+			{Name: "golf", Paths: "alpha.golf", OrgId: defaultOrgID},
+		}, get)
+	})
+}
+
+func Test_folder_Journal_Error(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "journal.ndjson")
+	err := os.WriteFile(path, []byte("not json\n{\"seq\":2,\"op\":\"move\"}\n"), 0644)
+	assert.NoError(t, err)
+
+	_, err = folder.OpenJournal(path, 0)
+	assert.ErrorContains(t, err, "journal line 1")
+}
@@ -0,0 +1,39 @@
+package folder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Decodes a JSON array of folders in the same format as sample.json
+// Input: reader
+// Output: slice of folders, error
+// Errors: IO errors, invalid JSON
+func LoadFolders(r io.Reader) ([]Folder, error) {
+	folders := []Folder{}
+	if err := json.NewDecoder(r).Decode(&folders); err != nil {
+		return nil, fmt.Errorf("decoding folders: %w", err)
+	}
+
+	return folders, nil
+}
+
+// Decodes a JSON file of folders
+// Input: file path
+// Output: slice of folders, error
+// Errors: IO errors, invalid JSON
+func LoadFoldersFile(path string) ([]Folder, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	folders, err := LoadFolders(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return folders, nil
+}
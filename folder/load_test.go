@@ -0,0 +1,79 @@
+package folder_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_LoadFolders(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+
+	tests := [...]struct {
+		testName string
+		input    string
+		want     []folder.Folder
+		err      string
+	}{
+		{
+			testName: "Empty array",
+			input:    "[]",
+			want:     []folder.Folder{},
+		},
+		{
+			testName: "Folders",
+			input:    `[{"name": "alpha", "org_id": "` + folder.DefaultOrgID + `", "paths": "alpha"}]`,
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+			},
+		},
+		{
+			testName: "Invalid JSON",
+			input:    `[{"name": `,
+			err:      "decoding folders",
+		},
+		{
+			testName: "Invalid org_id",
+			input:    `[{"name": "alpha", "org_id": "nope", "paths": "alpha"}]`,
+			err:      "decoding folders",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			get, err := folder.LoadFolders(strings.NewReader(tt.input))
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, get)
+		})
+	}
+}
+
+func Test_folder_LoadFoldersFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "folders.json")
+	err := os.WriteFile(path, []byte(`[{"name": "alpha", "org_id": "`+folder.DefaultOrgID+`", "paths": "alpha"}]`), 0644)
+	assert.NoError(t, err)
+
+	get, err := folder.LoadFoldersFile(path)
+	assert.NoError(t, err)
+	assert.Len(t, get, 1)
+
+	_, err = folder.LoadFoldersFile(filepath.Join(dir, "missing.json"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	// The embedded sample data loads without the source tree
+	assert.NotEmpty(t, folder.GetSampleData())
+}
@@ -0,0 +1,448 @@
+package folder
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+// LtreeFormat selects how rows are written by ExportLtreeSQL.
+type LtreeFormat int
+
+const (
+	// LtreeInsert writes one INSERT statement per folder.
+	LtreeInsert LtreeFormat = iota
+	// LtreeCopy writes a single COPY ... FROM stdin block.
+	LtreeCopy
+)
+
+type LtreeExportOptions struct {
+	// Table defaults to "folders"
+	Table  string
+	Format LtreeFormat
+	// EscapeHyphens escapes '-' in labels for PostgreSQL before 16, which only allows [A-Za-z0-9_]
+	EscapeHyphens bool
+}
+
+// LtreeImportOptions controls how ImportLtreeCopy and ImportLtreeCSV read labels.
+type LtreeImportOptions struct {
+	// Unescape decodes labels written by ExportLtreeSQL, by default labels are read as they are,
+	// so '_' in dumps of other tables is kept
+	Unescape bool
+}
+
+// EscapeLtreeLabel turns a folder name into a valid ltree label.
+// '_' becomes "__" and every other byte outside [A-Za-z0-9-] becomes '_' followed by two hex digits,
+// so the original name can always be recovered with UnescapeLtreeLabel.
+func EscapeLtreeLabel(name string, escapeHyphens bool) string {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c == '_':
+			b.WriteString("__")
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-' && !escapeHyphens:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "_%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// UnescapeLtreeLabel reverses EscapeLtreeLabel.
+func UnescapeLtreeLabel(label string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(label); i++ {
+		if label[i] != '_' {
+			b.WriteByte(label[i])
+			continue
+		}
+
+		if i+1 < len(label) && label[i+1] == '_' {
+			b.WriteByte('_')
+			i++
+			continue
+		}
+		if i+2 >= len(label) {
+			return "", fmt.Errorf("invalid escape at end of label %q", label)
+		}
+		c, err := strconv.ParseUint(label[i+1:i+3], 16, 8)
+		if err != nil {
+			return "", fmt.Errorf("invalid escape in label %q", label)
+		}
+		b.WriteByte(byte(c))
+		i += 2
+	}
+	return b.String(), nil
+}
+
+// Converts a dotted folder path into an ltree path
+func toLtree(paths string, escapeHyphens bool) string {
+	labels := strings.Split(paths, ".")
+	for i, label := range labels {
+		labels[i] = EscapeLtreeLabel(label, escapeHyphens)
+	}
+	return strings.Join(labels, ".")
+}
+
+// Converts an ltree path back into a dotted folder path
+func fromLtree(path string, opts LtreeImportOptions) (string, error) {
+	if !opts.Unescape {
+		return path, nil
+	}
+
+	labels := strings.Split(path, ".")
+	for i, label := range labels {
+		name, err := UnescapeLtreeLabel(label)
+		if err != nil {
+			return "", err
+		}
+		labels[i] = name
+	}
+	return strings.Join(labels, "."), nil
+}
+
+func quoteSQL(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func quoteIdent(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+var copyEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// Writes a PostgreSQL script that creates an ltree table and fills it with the folders
+// Input: writer, folders, export options
+// Output: error
+// Errors: IO errors
+func ExportLtreeSQL(w io.Writer, folders []Folder, opts LtreeExportOptions) error {
+	table := opts.Table
+	if table == "" {
+		table = "folders"
+	}
+	ident := quoteIdent(table)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "CREATE EXTENSION IF NOT EXISTS ltree;\n\n")
+	fmt.Fprintf(bw, "CREATE TABLE IF NOT EXISTS %s (\n\tname text NOT NULL,\n\torg_id uuid NOT NULL,\n\tpath ltree NOT NULL\n);\n", ident)
+	fmt.Fprintf(bw, "CREATE INDEX IF NOT EXISTS %s ON %s USING GIST (path);\n\n", quoteIdent(table+"_path_idx"), ident)
+
+	switch opts.Format {
+	case LtreeInsert:
+		for _, folder := range folders {
+			fmt.Fprintf(bw, "INSERT INTO %s (name, org_id, path) VALUES (%s, %s, %s);\n",
+				ident, quoteSQL(folder.Name), quoteSQL(folder.OrgId.String()), quoteSQL(toLtree(folder.Paths, opts.EscapeHyphens)))
+		}
+	case LtreeCopy:
+		fmt.Fprintf(bw, "COPY %s (name, org_id, path) FROM stdin;\n", ident)
+		for _, folder := range folders {
+			fmt.Fprintf(bw, "%s\t%s\t%s\n",
+				copyEscaper.Replace(folder.Name), folder.OrgId.String(), copyEscaper.Replace(toLtree(folder.Paths, opts.EscapeHyphens)))
+		}
+		fmt.Fprintf(bw, "\\.\n")
+	default:
+		return fmt.Errorf("unknown ltree format %d", opts.Format)
+	}
+
+	return bw.Flush()
+}
+
+// Maps the name, org_id and path columns to their position in a row
+type ltreeColumns struct {
+	name, orgID, path int
+	opts              LtreeImportOptions
+}
+
+func newLtreeColumns(header []string, opts LtreeImportOptions) (ltreeColumns, error) {
+	cols := ltreeColumns{name: -1, orgID: -1, path: -1, opts: opts}
+	for i, h := range header {
+		switch strings.Trim(strings.TrimSpace(h), `"`) {
+		case "name":
+			cols.name = i
+		case "org_id":
+			cols.orgID = i
+		case "path", "paths":
+			cols.path = i
+		}
+	}
+	if cols.orgID == -1 || cols.path == -1 {
+		return cols, errors.New("columns must include org_id and path")
+	}
+	return cols, nil
+}
+
+// Builds a folder from one row of a dump, the name falls back to the last label of the path
+func (c ltreeColumns) folder(row []string) (Folder, error) {
+	if c.name >= len(row) || c.orgID >= len(row) || c.path >= len(row) {
+		return Folder{}, fmt.Errorf("expected at least %d columns, got %d", max(c.name, c.orgID, c.path)+1, len(row))
+	}
+
+	orgID, err := uuid.FromString(row[c.orgID])
+	if err != nil {
+		return Folder{}, fmt.Errorf("invalid org_id: %w", err)
+	}
+	paths, err := fromLtree(row[c.path], c.opts)
+	if err != nil {
+		return Folder{}, err
+	}
+
+	name := paths[strings.LastIndex(paths, ".")+1:]
+	if c.name != -1 {
+		name = row[c.name]
+	}
+	return Folder{Name: name, OrgId: orgID, Paths: paths}, nil
+}
+
+// Reads folders from a PostgreSQL dump, either a pg_dump style script or the raw output of COPY ... TO STDOUT.
+// A script is read from its first COPY block, or from single line INSERT statements like those ExportLtreeSQL writes,
+// and their column lists decide the column order. Raw output must use the (name, org_id, path) order.
+// Input: reader, import options
+// Output: slice of folders, error
+// Errors: IO errors, no COPY block or INSERT statements, bad rows reported with their line number
+func ImportLtreeCopy(r io.Reader, opts LtreeImportOptions) ([]Folder, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	cols := ltreeColumns{name: 0, orgID: 1, path: 2, opts: opts}
+	folders := []Folder{}
+	inCopy, found := false, false
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+
+		if !inCopy {
+			upper := strings.ToUpper(strings.TrimSpace(text))
+			if strings.HasPrefix(upper, "INSERT INTO ") {
+				folder, err := insertFolder(text, opts)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", line, err)
+				}
+				folders = append(folders, folder)
+				found = true
+				continue
+			}
+			if strings.HasPrefix(upper, "COPY ") && strings.HasSuffix(upper, "FROM STDIN;") {
+				open, end := strings.Index(text, "("), strings.Index(text, ")")
+				if open != -1 && end > open {
+					var err error
+					cols, err = newLtreeColumns(strings.Split(text[open+1:end], ","), opts)
+					if err != nil {
+						return nil, fmt.Errorf("line %d: %w", line, err)
+					}
+				}
+				inCopy, found = true, true
+				continue
+			}
+
+			// Raw COPY output starts with data straight away, anything else is script to skip
+			if line > 1 || !strings.Contains(text, "\t") {
+				continue
+			}
+			inCopy, found = true, true
+		}
+
+		if text == `\.` {
+			break
+		}
+
+		fields := strings.Split(text, "\t")
+		for i, field := range fields {
+			unquoted, err := unescapeCopy(field)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			fields[i] = unquoted
+		}
+		folder, err := cols.folder(fields)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		folders = append(folders, folder)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errors.New("no COPY block or INSERT statements found")
+	}
+	return folders, nil
+}
+
+// Builds a folder from a single row INSERT INTO table [(columns)] VALUES (...); statement
+func insertFolder(stmt string, opts LtreeImportOptions) (Folder, error) {
+	values := strings.Index(strings.ToUpper(stmt), " VALUES")
+	if values == -1 {
+		return Folder{}, errors.New("expected INSERT ... VALUES")
+	}
+
+	cols := ltreeColumns{name: 0, orgID: 1, path: 2, opts: opts}
+	if open, end := strings.Index(stmt[:values], "("), strings.LastIndex(stmt[:values], ")"); open != -1 && end > open {
+		var err error
+		cols, err = newLtreeColumns(strings.Split(stmt[open+1:end], ","), opts)
+		if err != nil {
+			return Folder{}, err
+		}
+	}
+
+	row, err := sqlValues(stmt[values+len(" VALUES"):])
+	if err != nil {
+		return Folder{}, err
+	}
+	return cols.folder(row)
+}
+
+// Splits a single parenthesised VALUES list of string literals, casts such as 'a.b'::ltree are dropped
+func sqlValues(s string) ([]string, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "(") {
+		return nil, errors.New("expected ( after VALUES")
+	}
+
+	var row []string
+	for i := 1; ; {
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		if i == len(s) || s[i] != '\'' {
+			return nil, errors.New("expected a quoted value, only string literals are read")
+		}
+
+		var b strings.Builder
+		for i++; ; i++ {
+			if i == len(s) {
+				return nil, errors.New("unterminated string literal")
+			}
+			if s[i] == '\'' {
+				if i+1 < len(s) && s[i+1] == '\'' {
+					b.WriteByte('\'')
+					i++
+					continue
+				}
+				i++
+				break
+			}
+			b.WriteByte(s[i])
+		}
+		row = append(row, b.String())
+
+		if strings.HasPrefix(s[i:], "::") {
+			i += 2
+			for i < len(s) && (s[i] == '_' || s[i] == '.' || s[i] >= 'a' && s[i] <= 'z' || s[i] >= 'A' && s[i] <= 'Z' || s[i] >= '0' && s[i] <= '9') {
+				i++
+			}
+		}
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		switch {
+		case i < len(s) && s[i] == ',':
+			i++
+		case i < len(s) && s[i] == ')':
+			if rest := strings.TrimSpace(s[i+1:]); rest != ";" && rest != "" {
+				return nil, errors.New("only one row per INSERT is read")
+			}
+			return row, nil
+		default:
+			return nil, errors.New("expected , or ) between values")
+		}
+	}
+}
+
+// Reverses the backslash escapes of the COPY text format
+func unescapeCopy(field string) (string, error) {
+	if field == `\N` {
+		return "", errors.New("unexpected NULL")
+	}
+	if !strings.Contains(field, `\`) {
+		return field, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] != '\\' || i+1 == len(field) {
+			b.WriteByte(field[i])
+			continue
+		}
+		i++
+		switch field[i] {
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'v':
+			b.WriteByte('\v')
+		case 'x':
+			end := i + 1
+			for end < len(field) && end < i+3 && strings.IndexByte("0123456789abcdefABCDEF", field[end]) != -1 {
+				end++
+			}
+			c, err := strconv.ParseUint(field[i+1:end], 16, 8)
+			if err != nil {
+				return "", fmt.Errorf("invalid escape in %q", field)
+			}
+			b.WriteByte(byte(c))
+			i = end - 1
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			end := i
+			for end < len(field) && end < i+3 && field[end] >= '0' && field[end] <= '7' {
+				end++
+			}
+			c, _ := strconv.ParseUint(field[i:end], 8, 8)
+			b.WriteByte(byte(c))
+			i = end - 1
+		default:
+			b.WriteByte(field[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// Reads folders from the output of COPY ... TO STDOUT WITH (FORMAT csv, HEADER)
+// Input: reader, import options
+// Output: slice of folders, error
+// Errors: IO errors, missing columns, bad rows reported with their line number
+func ImportLtreeCSV(r io.Reader, opts LtreeImportOptions) ([]Folder, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	cols, err := newLtreeColumns(header, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	folders := []Folder{}
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		folder, err := cols.folder(row)
+		if err != nil {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		folders = append(folders, folder)
+	}
+	return folders, nil
+}
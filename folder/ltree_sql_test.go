@@ -0,0 +1,180 @@
+package folder_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_EscapeLtreeLabel(t *testing.T) {
+	t.Parallel()
+
+	tests := [...]struct {
+		testName      string
+		name          string
+		escapeHyphens bool
+		want          string
+	}{
+		{testName: "Plain name", name: "alpha", want: "alpha"},
+		{testName: "Hyphens kept", name: "creative-scalphunter", want: "creative-scalphunter"},
+		{testName: "Hyphens escaped", name: "creative-scalphunter", escapeHyphens: true, want: "creative_2Dscalphunter"},
+		{testName: "Underscore doubled", name: "my_folder", want: "my__folder"},
+		{testName: "Spaces and quotes", name: "bob's docs", want: "bob_27s_20docs"},
+		{testName: "Multi-byte characters", name: "café", want: "caf_C3_A9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			get := folder.EscapeLtreeLabel(tt.name, tt.escapeHyphens)
+			assert.Equal(t, tt.want, get)
+
+			back, err := folder.UnescapeLtreeLabel(get)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.name, back)
+		})
+	}
+}
+
+func Test_folder_ExportLtreeSQL(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bob's docs", Paths: "alpha.bob's docs", OrgId: defaultOrgID},
+		{Name: "tab\there", Paths: "alpha.bob's docs.tab\there", OrgId: defaultOrgID},
+	}
+
+	t.Run("Insert statements", func(t *testing.T) {
+		var b bytes.Buffer
+		err := folder.ExportLtreeSQL(&b, folders, folder.LtreeExportOptions{Table: "docs"})
+		assert.NoError(t, err)
+		assert.Contains(t, b.String(), `CREATE TABLE IF NOT EXISTS "docs" (`)
+		assert.Contains(t, b.String(),
+			`INSERT INTO "docs" (name, org_id, path) VALUES ('bob''s docs', '`+folder.DefaultOrgID+`', 'alpha.bob_27s_20docs');`)
+	})
+
+	t.Run("Copy round trip", func(t *testing.T) {
+		var b bytes.Buffer
+		err := folder.ExportLtreeSQL(&b, folders, folder.LtreeExportOptions{Format: folder.LtreeCopy})
+		assert.NoError(t, err)
+		assert.Contains(t, b.String(), "tab\\there\t")
+
+		get, err := folder.ImportLtreeCopy(&b, folder.LtreeImportOptions{Unescape: true})
+		assert.NoError(t, err)
+		assert.Equal(t, folders, get)
+	})
+
+	t.Run("Insert round trip", func(t *testing.T) {
+		var b bytes.Buffer
+		err := folder.ExportLtreeSQL(&b, folders, folder.LtreeExportOptions{Format: folder.LtreeInsert})
+		assert.NoError(t, err)
+
+		get, err := folder.ImportLtreeCopy(&b, folder.LtreeImportOptions{Unescape: true})
+		assert.NoError(t, err)
+		assert.Equal(t, folders, get)
+	})
+}
+
+func Test_folder_ImportLtree(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+
+	t.Run("pg_dump script with reordered columns", func(t *testing.T) {
+		dump := strings.Join([]string{
+			"SET client_encoding = 'UTF8';",
+			"COPY public.folders (org_id, path, name) FROM stdin;",
+			folder.DefaultOrgID + "\ttop\ttop",
+			folder.DefaultOrgID + "\ttop.my_docs\tmy_docs",
+			folder.DefaultOrgID + "\ttop.a_1b\ta_1b",
+			`\.`,
+			"ALTER TABLE ONLY public.folders ADD CONSTRAINT folders_pkey PRIMARY KEY (path);",
+		}, "\n")
+
+		// Labels are plain ltree, underscores are part of the name
+		get, err := folder.ImportLtreeCopy(strings.NewReader(dump), folder.LtreeImportOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, []folder.Folder{
+			{Name: "top", Paths: "top", OrgId: defaultOrgID},
+			{Name: "my_docs", Paths: "top.my_docs", OrgId: defaultOrgID},
+			{Name: "a_1b", Paths: "top.a_1b", OrgId: defaultOrgID},
+		}, get)
+	})
+
+	t.Run("Plain CSV without names", func(t *testing.T) {
+		dump := "path,org_id\n" +
+			"top," + folder.DefaultOrgID + "\n" +
+			"top.my_docs," + folder.DefaultOrgID + "\n"
+
+		get, err := folder.ImportLtreeCSV(strings.NewReader(dump), folder.LtreeImportOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, []folder.Folder{
+			{Name: "top", Paths: "top", OrgId: defaultOrgID},
+			{Name: "my_docs", Paths: "top.my_docs", OrgId: defaultOrgID},
+		}, get)
+	})
+
+	t.Run("Escaped CSV with header", func(t *testing.T) {
+		dump := "path,org_id\n" +
+			"alpha," + folder.DefaultOrgID + "\n" +
+			"alpha.caf_C3_A9," + folder.DefaultOrgID + "\n"
+
+		get, err := folder.ImportLtreeCSV(strings.NewReader(dump), folder.LtreeImportOptions{Unescape: true})
+		assert.NoError(t, err)
+		assert.Equal(t, []folder.Folder{
+			{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+			{Name: "café", Paths: "alpha.café", OrgId: defaultOrgID},
+		}, get)
+	})
+
+	t.Run("Bad row reports its line", func(t *testing.T) {
+		dump := "alpha\t" + folder.DefaultOrgID + "\talpha\n" +
+			"bravo\tnot-a-uuid\talpha.bravo\n"
+
+		_, err := folder.ImportLtreeCopy(strings.NewReader(dump), folder.LtreeImportOptions{})
+		assert.ErrorContains(t, err, "line 2: invalid org_id")
+	})
+
+	t.Run("pg_dump inserts with casts", func(t *testing.T) {
+		dump := strings.Join([]string{
+			"INSERT INTO public.folders (org_id, path, name) VALUES ('" + folder.DefaultOrgID + "', 'top'::public.ltree, 'top');",
+			"INSERT INTO public.folders (org_id, path, name) VALUES ('" + folder.DefaultOrgID + "', 'top.my_docs'::public.ltree, 'my_docs');",
+		}, "\n")
+
+		get, err := folder.ImportLtreeCopy(strings.NewReader(dump), folder.LtreeImportOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, []folder.Folder{
+			{Name: "top", Paths: "top", OrgId: defaultOrgID},
+			{Name: "my_docs", Paths: "top.my_docs", OrgId: defaultOrgID},
+		}, get)
+	})
+
+	t.Run("Script without data", func(t *testing.T) {
+		dump := "CREATE EXTENSION IF NOT EXISTS ltree;\nSET client_encoding = 'UTF8';\n"
+
+		_, err := folder.ImportLtreeCopy(strings.NewReader(dump), folder.LtreeImportOptions{})
+		assert.ErrorContains(t, err, "no COPY block or INSERT statements found")
+	})
+
+	t.Run("Insert of several rows", func(t *testing.T) {
+		dump := "SET client_encoding = 'UTF8';\n" +
+			"INSERT INTO folders VALUES ('alpha', '" + folder.DefaultOrgID + "', 'alpha'), ('bravo', '" + folder.DefaultOrgID + "', 'bravo');\n"
+
+		_, err := folder.ImportLtreeCopy(strings.NewReader(dump), folder.LtreeImportOptions{})
+		assert.ErrorContains(t, err, "line 2: only one row per INSERT is read")
+	})
+
+	t.Run("Bad CSV row reports its line", func(t *testing.T) {
+		dump := "name,org_id,path\n" +
+			"alpha," + folder.DefaultOrgID + ",alpha\n" +
+			"bravo," + folder.DefaultOrgID + ",alpha._ZZ\n"
+
+		_, err := folder.ImportLtreeCSV(strings.NewReader(dump), folder.LtreeImportOptions{Unescape: true})
+		assert.ErrorContains(t, err, "line 3: invalid escape")
+	})
+}
@@ -1,9 +1,11 @@
 package folder
 
 import (
-	"errors"
+	"fmt"
 
 	"strings"
+
+	"github.com/gofrs/uuid"
 )
 
 // Move a source folder and its children into another folder
@@ -11,29 +13,66 @@ import (
 // Output: slice of folders, IO errors
 // Errors: Moving folders to a different organisation, moving a folder to its child
 func (f *driver) MoveFolder(name string, dst string) ([]Folder, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	// Get indices of folders of interest
 	start, dest, err := f.getFolderIndices(name, dst)
 	if err != nil {
 		return nil, err
 	}
+	return f.moveFolder(start, dest)
+}
+
+// Move a folder of an organisation and its children into another folder of the organisation
+// Input: organisation ID, source folder name, destination folder name
+// Output: slice of folders, IO errors
+// Errors: Non-existent source or destination in the organisation, moving a folder to itself or its child
+func (f *driver) MoveFolderInOrg(orgID uuid.UUID, name string, dst string) ([]Folder, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	start, dest := f.findFolderIndexInOrg(orgID, name), f.findFolderIndexInOrg(orgID, dst)
+	if start == -1 {
+		return nil, fmt.Errorf("source %w in the specified organisation", ErrFolderNotFound)
+	} else if dest == -1 {
+		return nil, fmt.Errorf("destination %w in the specified organisation", ErrFolderNotFound)
+	} else if start == dest {
+		return nil, fmt.Errorf("%w to itself", ErrInvalidMove)
+	}
+	return f.moveFolder(start, dest)
+}
 
+// Moves the folder at one index into the folder at another
+// Input: index of source folder, index of destination folder
+// Output: slice of folders, IO errors
+// Errors: Moving folders to a different organisation, moving a folder to its child, errors from the store
+func (f *driver) moveFolder(start int, dest int) ([]Folder, error) {
 	nodeToMove := f.folders[start]
 	destination := f.folders[dest]
 
 	// Handle cases where folders are in different organisations or where one is a child of the other
 	if nodeToMove.OrgId != destination.OrgId {
-		return nil, errors.New("cannot move a folder to a different organisation")
+		return nil, fmt.Errorf("%w to a different organisation", ErrInvalidMove)
 	} else if strings.HasPrefix(destination.Paths, nodeToMove.Paths+".") {
-		return nil, errors.New("cannot move folder to a child of itself")
+		return nil, fmt.Errorf("%w to a child of itself", ErrInvalidMove)
 	}
 
-	// Update child nodes with new paths
+	// Persist the move before touching the in-memory folders
 	newPath := destination.Paths + "." + nodeToMove.Name
+	m := Mutation{Op: OpMove, OrgId: nodeToMove.OrgId, Name: nodeToMove.Name, From: nodeToMove.Paths, To: newPath}
+	events, err := f.persist(m)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update child nodes with new paths
 	oldPath := nodeToMove.Paths + "."
 	f.folders[start].Paths = newPath
-	f.updateFolderPaths(oldPath, newPath)
+	f.updateFolderPaths(nodeToMove.OrgId, oldPath, newPath)
+	f.publish(m, events)
 
-	return f.folders, nil
+	return append([]Folder{}, f.folders...), nil
 }
 
 // Finds and returns the indices of the source and destination folder if valid
@@ -55,23 +94,23 @@ func (f *driver) getFolderIndices(name string, dst string) (int, int, error) {
 
 	// Handle errors for non-existent folders or moving a folder to itself
 	if start == -1 {
-		return -1, -1, errors.New("source folder does not exist")
+		return -1, -1, fmt.Errorf("source %w", ErrFolderNotFound)
 	} else if dest == -1 {
-		return -1, -1, errors.New("destination folder does not exist")
+		return -1, -1, fmt.Errorf("destination %w", ErrFolderNotFound)
 	} else if start == dest {
-		return -1, -1, errors.New("cannot move a folder to itself")
+		return -1, -1, fmt.Errorf("%w to itself", ErrInvalidMove)
 	}
 
 	return start, dest, nil
 }
 
-// Update the paths of folders that contain the old path with the new path
-// Input: original path of parent, new path of parent
+// Update the paths of folders in an organisation that contain the old path with the new path
+// Input: organisation ID, original path of parent, new path of parent
 // Output: None
-func (f *driver) updateFolderPaths(oldPath string, newPath string) {
+func (f *driver) updateFolderPaths(orgID uuid.UUID, oldPath string, newPath string) {
 	// For each child part of an input path, change their path to a new path
 	for i := range f.folders {
-		if strings.HasPrefix(f.folders[i].Paths, oldPath) {
+		if f.folders[i].OrgId == orgID && strings.HasPrefix(f.folders[i].Paths, oldPath) {
 			leftover := strings.TrimPrefix(f.folders[i].Paths, oldPath)
 			f.folders[i].Paths = newPath + "." + leftover
 		}
@@ -69,11 +69,13 @@ func Test_folder_MoveFolder(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.testName, func(t *testing.T) {
-			f := folder.NewDriver(tt.folders)
-			get, _ := f.MoveFolder(tt.start, tt.destination)
-			assert.Equal(t, tt.want, get)
-		})
+		for _, d := range testDrivers {
+			t.Run(d.name+"/"+tt.testName, func(t *testing.T) {
+				f := d.new(t, tt.folders)
+				get, _ := f.MoveFolder(tt.start, tt.destination)
+				assert.Equal(t, tt.want, get)
+			})
+		}
 	}
 }
 
@@ -102,6 +104,7 @@ func Test_folder_MoveFolder_Error(t *testing.T) {
 		orgID       uuid.UUID
 		folders     []folder.Folder
 		want        string
+		is          error
 	}{
 		{
 			testName:    "Example 1: Move folder to a child of itself",
@@ -109,7 +112,8 @@ func Test_folder_MoveFolder_Error(t *testing.T) {
 			destination: "charlie",
 			orgID:       defaultOrdID,
 			folders:     example1,
-			want:        "cannot move folder to a child of itself",
+			want:        "cannot move a folder to a child of itself",
+			is:          folder.ErrInvalidMove,
 		},
 		{
 			testName:    "Example 2: Move a folder to itself",
@@ -118,6 +122,7 @@ func Test_folder_MoveFolder_Error(t *testing.T) {
 			orgID:       defaultOrdID,
 			folders:     example1,
 			want:        "cannot move a folder to itself",
+			is:          folder.ErrInvalidMove,
 		},
 		{
 			testName:    "Example 3: Move a folder to a different organisation",
@@ -126,6 +131,7 @@ func Test_folder_MoveFolder_Error(t *testing.T) {
 			orgID:       defaultOrdID,
 			folders:     example1,
 			want:        "cannot move a folder to a different organisation",
+			is:          folder.ErrInvalidMove,
 		},
 		{
 			testName:    "Example 4: Source folder does not exist",
@@ -134,6 +140,7 @@ func Test_folder_MoveFolder_Error(t *testing.T) {
 			orgID:       defaultOrdID,
 			folders:     example1,
 			want:        "source folder does not exist",
+			is:          folder.ErrFolderNotFound,
 		},
 		{
 			testName:    "Example 5: Destination folder does not exist",
@@ -142,14 +149,87 @@ func Test_folder_MoveFolder_Error(t *testing.T) {
 			orgID:       defaultOrdID,
 			folders:     example1,
 			want:        "destination folder does not exist",
+			is:          folder.ErrFolderNotFound,
 		},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.testName, func(t *testing.T) {
-			f := folder.NewDriver(tt.folders)
-			_, err := f.MoveFolder(tt.start, tt.destination)
-			assert.ErrorContains(t, err, tt.want)
+		for _, d := range testDrivers {
+			t.Run(d.name+"/"+tt.testName, func(t *testing.T) {
+				f := d.new(t, tt.folders)
+				_, err := f.MoveFolder(tt.start, tt.destination)
+				assert.ErrorContains(t, err, tt.want)
+				assert.ErrorIs(t, err, tt.is)
+			})
+		}
+	}
+}
+
+func Test_folder_MoveFolder_OtherOrganisation(t *testing.T) {
+	t.Parallel()
+
+	defaultOrdID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	secondaryOrdID := uuid.Must(uuid.NewV4())
+
+	// Both organisations have an alpha.xray path, only the moving organisation changes
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: secondaryOrdID},
+		{Name: "xray", Paths: "alpha.xray", OrgId: secondaryOrdID},
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrdID},
+		{Name: "xray", Paths: "alpha.xray", OrgId: defaultOrdID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrdID},
+	}
+
+	for _, d := range testDrivers {
+		t.Run(d.name, func(t *testing.T) {
+			f := d.new(t, example1)
+			_, err := f.MoveFolder("alpha", "golf")
+			assert.NoError(t, err)
+			assert.Equal(t, example1[:2], f.GetFoldersByOrgID(secondaryOrdID))
+			assert.Equal(t, []folder.Folder{
+				{Name: "alpha", Paths: "golf.alpha", OrgId: defaultOrdID},
+				{Name: "xray", Paths: "golf.alpha.xray", OrgId: defaultOrdID},
+				{Name: "golf", Paths: "golf", OrgId: defaultOrdID},
+			}, f.GetFoldersByOrgID(defaultOrdID))
+			assert.Empty(t, folder.ValidateFolders(append(f.GetFoldersByOrgID(secondaryOrdID), f.GetFoldersByOrgID(defaultOrdID)...)))
+		})
+	}
+}
+
+func Test_folder_MoveFolderInOrg(t *testing.T) {
+	t.Parallel()
+
+	defaultOrdID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	secondaryOrdID := uuid.Must(uuid.NewV4())
+
+	// The secondary organisation comes last, so MoveFolder would pick its alpha
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrdID},
+		{Name: "xray", Paths: "alpha.xray", OrgId: defaultOrdID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrdID},
+		{Name: "alpha", Paths: "alpha", OrgId: secondaryOrdID},
+		{Name: "hotel", Paths: "hotel", OrgId: secondaryOrdID},
+	}
+
+	for _, d := range testDrivers {
+		t.Run(d.name, func(t *testing.T) {
+			f := d.new(t, example1)
+			_, err := f.MoveFolderInOrg(defaultOrdID, "alpha", "golf")
+			assert.NoError(t, err)
+			assert.Equal(t, []folder.Folder{
+				{Name: "alpha", Paths: "golf.alpha", OrgId: defaultOrdID},
+				{Name: "xray", Paths: "golf.alpha.xray", OrgId: defaultOrdID},
+				{Name: "golf", Paths: "golf", OrgId: defaultOrdID},
+			}, f.GetFoldersByOrgID(defaultOrdID))
+			assert.Equal(t, example1[3:], f.GetFoldersByOrgID(secondaryOrdID))
+
+			_, err = f.MoveFolderInOrg(defaultOrdID, "golf", "hotel")
+			assert.ErrorIs(t, err, folder.ErrFolderNotFound)
+			assert.ErrorContains(t, err, "destination folder does not exist in the specified organisation")
+			_, err = f.MoveFolderInOrg(secondaryOrdID, "xray", "hotel")
+			assert.ErrorContains(t, err, "source folder does not exist in the specified organisation")
+			_, err = f.MoveFolderInOrg(defaultOrdID, "golf", "xray")
+			assert.ErrorIs(t, err, folder.ErrInvalidMove)
 		})
 	}
 }
@@ -0,0 +1,80 @@
+package folder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// Longest line ReadFoldersNDJSON accepts, far more than any single folder needs
+const maxNDJSONLine = 1024 * 1024
+
+// Streams folders from newline-delimited JSON, one folder object per line, blank lines are skipped
+// Memory use stays constant however many folders the reader holds.
+// Iteration stops after the first error, which is yielded with a zero folder.
+// Input: reader
+// Output: iterator of folders and errors
+// Errors: IO errors, invalid JSON reported with its line number
+func ReadFoldersNDJSON(r io.Reader) iter.Seq2[Folder, error] {
+	return func(yield func(Folder, error) bool) {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLine)
+
+		for line := 1; scanner.Scan(); line++ {
+			b := bytes.TrimSpace(scanner.Bytes())
+			if len(b) == 0 {
+				continue
+			}
+
+			var folder Folder
+			if err := json.Unmarshal(b, &folder); err != nil {
+				yield(Folder{}, fmt.Errorf("line %d: %w", line, err))
+				return
+			}
+			if !yield(folder, nil) {
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			yield(Folder{}, err)
+		}
+	}
+}
+
+// NDJSONWriter writes folders as newline-delimited JSON through a buffer.
+// Call Flush once every folder has been written.
+type NDJSONWriter struct {
+	writer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	writer := bufio.NewWriter(w)
+	return &NDJSONWriter{writer: writer, encoder: json.NewEncoder(writer)}
+}
+
+func (w *NDJSONWriter) Write(folder Folder) error {
+	return w.encoder.Encode(folder)
+}
+
+func (w *NDJSONWriter) Flush() error {
+	return w.writer.Flush()
+}
+
+// Writes every folder from an iterator as newline-delimited JSON
+// Input: writer, iterator of folders
+// Output: error
+// Errors: IO errors
+func WriteFoldersNDJSON(w io.Writer, folders iter.Seq[Folder]) error {
+	writer := NewNDJSONWriter(w)
+	for folder := range folders {
+		if err := writer.Write(folder); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
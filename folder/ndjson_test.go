@@ -0,0 +1,103 @@
+package folder_test
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_NDJSON_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+	}
+
+	var b bytes.Buffer
+	assert.NoError(t, folder.WriteFoldersNDJSON(&b, slices.Values(folders)))
+	assert.Equal(t, 2, strings.Count(b.String(), "\n"))
+
+	get := []folder.Folder{}
+	for f, err := range folder.ReadFoldersNDJSON(&b) {
+		assert.NoError(t, err)
+		get = append(get, f)
+	}
+	assert.Equal(t, folders, get)
+}
+
+func Test_folder_ReadFoldersNDJSON(t *testing.T) {
+	t.Parallel()
+
+	valid := `{"name":"alpha","org_id":"` + folder.DefaultOrgID + `","paths":"alpha"}`
+
+	tests := [...]struct {
+		testName string
+		input    string
+		count    int
+		err      string
+	}{
+		{
+			testName: "Empty input",
+			input:    "",
+			count:    0,
+		},
+		{
+			testName: "Blank lines skipped",
+			input:    valid + "\n\n" + valid + "\n",
+			count:    2,
+		},
+		{
+			testName: "No trailing newline",
+			input:    valid + "\n" + valid,
+			count:    2,
+		},
+		{
+			testName: "Invalid line stops iteration",
+			input:    valid + "\n{oops\n" + valid + "\n",
+			count:    1,
+			err:      "line 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			count := 0
+			var lastErr error
+			for _, err := range folder.ReadFoldersNDJSON(strings.NewReader(tt.input)) {
+				if err != nil {
+					lastErr = err
+					continue
+				}
+				count++
+			}
+			assert.Equal(t, tt.count, count)
+			if tt.err != "" {
+				assert.ErrorContains(t, lastErr, tt.err)
+			} else {
+				assert.NoError(t, lastErr)
+			}
+		})
+	}
+}
+
+func Test_folder_ReadFoldersNDJSON_Break(t *testing.T) {
+	t.Parallel()
+
+	valid := `{"name":"alpha","org_id":"` + folder.DefaultOrgID + `","paths":"alpha"}` + "\n"
+
+	count := 0
+	for range folder.ReadFoldersNDJSON(strings.NewReader(strings.Repeat(valid, 10))) {
+		count++
+		if count == 3 {
+			break
+		}
+	}
+	assert.Equal(t, 3, count)
+}
@@ -0,0 +1,143 @@
+package folder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gofrs/uuid"
+)
+
+// PatchOp is one operation of a patch, naming folders within an organisation like the driver's InOrg methods.
+type PatchOp struct {
+	Op Op `json:"op"`
+	// OrgId is the organisation the named folders are in, required for every operation
+	OrgId uuid.UUID `json:"org_id"`
+	Name  string    `json:"name"`
+	// Parent of a created folder, empty for a root folder
+	Parent string `json:"parent,omitempty"`
+	// Dst is the folder a moved folder goes into
+	Dst string `json:"dst,omitempty"`
+	// NewName of a renamed folder
+	NewName string `json:"new_name,omitempty"`
+}
+
+// Patch is a list of operations applied in order, stored as a JSON array.
+type Patch []PatchOp
+
+// Reads a patch from its JSON form
+// Input: reader
+// Output: patch, error
+// Errors: IO errors, invalid JSON, unknown fields, anything but a single array
+func ReadPatch(r io.Reader) (Patch, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	var patch Patch
+	if err := decoder.Decode(&patch); err != nil {
+		return nil, err
+	} else if patch == nil {
+		return nil, errors.New("patch must be a JSON array")
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the patch")
+	}
+	return patch, nil
+}
+
+// Runs the operation on a driver
+func (op PatchOp) apply(d IDriver) ([]Folder, error) {
+	if op.OrgId == uuid.Nil {
+		return nil, errors.New("organisation ID is required")
+	}
+
+	switch op.Op {
+	case OpCreate:
+		return d.CreateFolder(op.OrgId, op.Name, op.Parent)
+	case OpMove:
+		return d.MoveFolderInOrg(op.OrgId, op.Name, op.Dst)
+	case OpRename:
+		return d.RenameFolderInOrg(op.OrgId, op.Name, op.NewName)
+	case OpDelete:
+		return d.DeleteFolderInOrg(op.OrgId, op.Name)
+	default:
+		return nil, errors.New("unknown operation: " + string(op.Op))
+	}
+}
+
+type patchable interface {
+	Load() ([]Folder, error)
+	Apply(m Mutation) error
+}
+
+// Checks every operation of a patch applies, without changing the driver
+// Input: driver from this package, patch
+// Output: folders after the patch, error
+// Errors: Driver that cannot be patched, the first operation that fails, numbered from 0
+func CheckPatch(d IDriver, patch Patch) ([]Folder, error) {
+	folders, _, _, err := planPatch(d, patch)
+	return folders, err
+}
+
+// Applies every operation of a patch, or none of them
+// Operations are checked against a copy of the folders first, so a patch that does not apply leaves the driver as it was.
+// Input: driver from this package, patch
+// Output: folders after the patch, error
+// Errors: Driver that cannot be patched, the first operation that fails, numbered from 0, errors from the store
+func ApplyPatch(d IDriver, patch Patch) ([]Folder, error) {
+	folders, mutations, events, err := planPatch(d, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	p := d.(patchable)
+	for i, m := range mutations {
+		if err := p.Apply(m); err != nil {
+			return nil, rollbackPatch(p, mutations[:i], events[:i], err)
+		}
+	}
+	return folders, nil
+}
+
+// Reverses the mutations already applied when a later one fails
+// The planned events describe them, as the plan started from the same folders.
+func rollbackPatch(p patchable, mutations []Mutation, events [][]Event, err error) error {
+	for i := len(mutations) - 1; i >= 0; i-- {
+		for _, undo := range inverse(mutations[i:i+1], events[i]) {
+			if rollbackErr := p.Apply(undo); rollbackErr != nil {
+				return fmt.Errorf("patch partly applied: %w", errors.Join(err, rollbackErr))
+			}
+		}
+	}
+	return err
+}
+
+// Runs a patch against a copy of the driver's folders
+// Input: driver, patch
+// Output: folders after the patch, mutations it makes, events of each mutation, error
+func planPatch(d IDriver, patch Patch) ([]Folder, []Mutation, [][]Event, error) {
+	p, ok := d.(patchable)
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("cannot patch a %T", d)
+	}
+	folders, err := p.Load()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	plan := newDriver(folders, nil)
+	mutations := []Mutation{}
+	events := [][]Event{}
+	plan.observe(func(_ any, m Mutation, e []Event) {
+		mutations = append(mutations, m)
+		events = append(events, e)
+	})
+
+	for i, op := range patch {
+		if _, err := op.apply(plan); err != nil {
+			return nil, nil, nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Name, err)
+		}
+	}
+	return plan.folders, mutations, events, nil
+}
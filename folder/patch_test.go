@@ -0,0 +1,183 @@
+package folder_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_ApplyPatch(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	secondaryOrgID := uuid.Must(uuid.NewV4())
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+	}
+
+	tests := [...]struct {
+		testName string
+		patch    folder.Patch
+		want     []folder.Folder
+		err      string
+	}{
+		{
+			testName: "Every operation",
+			patch: folder.Patch{
+				{Op: folder.OpCreate, OrgId: defaultOrgID, Name: "hotel", Parent: "golf"},
+				{Op: folder.OpMove, OrgId: defaultOrgID, Name: "bravo", Dst: "hotel"},
+				{Op: folder.OpRename, OrgId: defaultOrgID, Name: "charlie", NewName: "delta"},
+				{Op: folder.OpDelete, OrgId: defaultOrgID, Name: "alpha"},
+			},
+			want: []folder.Folder{
+				{Name: "bravo", Paths: "golf.hotel.bravo", OrgId: defaultOrgID},
+				{Name: "delta", Paths: "golf.hotel.bravo.delta", OrgId: defaultOrgID},
+				{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+				{Name: "hotel", Paths: "golf.hotel", OrgId: defaultOrgID},
+			},
+		},
+		{
+			testName: "Operation depending on an earlier one",
+			patch: folder.Patch{
+				{Op: folder.OpDelete, OrgId: defaultOrgID, Name: "golf"},
+				{Op: folder.OpCreate, OrgId: defaultOrgID, Name: "golf", Parent: "charlie"},
+			},
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+				{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+				{Name: "golf", Paths: "alpha.bravo.charlie.golf", OrgId: defaultOrgID},
+			},
+		},
+		{
+			testName: "Failing operation applies nothing",
+			patch: folder.Patch{
+				{Op: folder.OpMove, OrgId: defaultOrgID, Name: "bravo", Dst: "golf"},
+				{Op: folder.OpMove, OrgId: defaultOrgID, Name: "golf", Dst: "charlie"},
+			},
+			err: "operation 1 (move golf): cannot move a folder to a child of itself",
+		},
+		{
+			testName: "Create without an organisation",
+			patch:    folder.Patch{{Op: folder.OpCreate, Name: "hotel"}},
+			err:      "operation 0 (create hotel): organisation ID is required",
+		},
+		{
+			testName: "Move without an organisation",
+			patch:    folder.Patch{{Op: folder.OpMove, Name: "bravo", Dst: "golf"}},
+			err:      "operation 0 (move bravo): organisation ID is required",
+		},
+		{
+			testName: "Folder in another organisation",
+			patch:    folder.Patch{{Op: folder.OpDelete, OrgId: secondaryOrgID, Name: "alpha"}},
+			err:      "operation 0 (delete alpha): folder does not exist in the specified organisation",
+		},
+		{
+			testName: "Unknown operation",
+			patch:    folder.Patch{{Op: "copy", OrgId: defaultOrgID, Name: "alpha"}},
+			err:      "operation 0 (copy alpha): unknown operation: copy",
+		},
+	}
+
+	for _, tt := range tests {
+		for _, d := range testDrivers {
+			t.Run(d.name+"/"+tt.testName, func(t *testing.T) {
+				f := d.new(t, example1)
+
+				checked, err := folder.CheckPatch(f, tt.patch)
+				if tt.err != "" {
+					assert.EqualError(t, err, tt.err)
+				} else {
+					assert.NoError(t, err)
+					assert.ElementsMatch(t, tt.want, checked)
+				}
+				assert.Equal(t, example1, f.GetFoldersByOrgID(defaultOrgID))
+
+				get, err := folder.ApplyPatch(f, tt.patch)
+				if tt.err != "" {
+					assert.EqualError(t, err, tt.err)
+					assert.Equal(t, example1, f.GetFoldersByOrgID(defaultOrgID))
+					return
+				}
+				assert.NoError(t, err)
+				assert.ElementsMatch(t, tt.want, get)
+				assert.ElementsMatch(t, tt.want, f.GetFoldersByOrgID(defaultOrgID))
+			})
+		}
+	}
+}
+
+// Store that fails one change, after a number of successful ones
+type failingStore struct {
+	*folder.MemoryStore
+	changes int
+}
+
+func (s *failingStore) Apply(m folder.Mutation) error {
+	s.changes--
+	if s.changes == -1 {
+		return errors.New("disk full")
+	}
+	return s.MemoryStore.Apply(m)
+}
+
+func Test_folder_ApplyPatch_Rollback(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+	}
+
+	// The rename fails after the move and delete were applied
+	store := &failingStore{MemoryStore: folder.NewMemoryStore(example1), changes: 2}
+	f, err := folder.NewDriverWithStore(store)
+	assert.NoError(t, err)
+
+	_, err = folder.ApplyPatch(f, folder.Patch{
+		{Op: folder.OpMove, OrgId: defaultOrgID, Name: "charlie", Dst: "golf"},
+		{Op: folder.OpDelete, OrgId: defaultOrgID, Name: "alpha"},
+		{Op: folder.OpRename, OrgId: defaultOrgID, Name: "golf", NewName: "hotel"},
+	})
+	assert.EqualError(t, err, "disk full")
+	assert.ElementsMatch(t, example1, f.GetFoldersByOrgID(defaultOrgID))
+	stored, err := store.Load()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, example1, stored)
+}
+
+func Test_folder_ReadPatch(t *testing.T) {
+	t.Parallel()
+
+	patch, err := folder.ReadPatch(strings.NewReader(`[
+		{"op": "create", "org_id": "c1556e17-b7c0-45a3-a6ae-9546248fb17a", "name": "hotel", "parent": "golf"},
+		{"op": "move", "org_id": "c1556e17-b7c0-45a3-a6ae-9546248fb17a", "name": "bravo", "dst": "hotel"}
+	]`))
+	assert.NoError(t, err)
+	assert.Equal(t, folder.Patch{
+		{Op: folder.OpCreate, OrgId: uuid.FromStringOrNil(folder.DefaultOrgID), Name: "hotel", Parent: "golf"},
+		{Op: folder.OpMove, OrgId: uuid.FromStringOrNil(folder.DefaultOrgID), Name: "bravo", Dst: "hotel"},
+	}, patch)
+
+	_, err = folder.ReadPatch(strings.NewReader(`[{"op": "move", "name": "bravo", "destination": "hotel"}]`))
+	assert.ErrorContains(t, err, "unknown field")
+	_, err = folder.ReadPatch(strings.NewReader(`[] trailing`))
+	assert.EqualError(t, err, "unexpected data after the patch")
+	_, err = folder.ReadPatch(strings.NewReader(`null`))
+	assert.EqualError(t, err, "patch must be a JSON array")
+
+	// An empty patch is still a patch
+	patch, err = folder.ReadPatch(strings.NewReader("[]\n"))
+	assert.NoError(t, err)
+	assert.Empty(t, patch)
+}
@@ -0,0 +1,98 @@
+package folder
+
+import (
+	"fmt"
+
+	"github.com/gofrs/uuid"
+)
+
+// Rename a folder and update the paths of its children
+// Input: folder name, new folder name
+// Output: slice of folders, IO errors
+// Errors: Non-existent folder, invalid new name, new name already used in the organisation
+func (f *driver) RenameFolder(name string, newName string) ([]Folder, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	index := f.findFolderIndex(name)
+	if index == -1 {
+		return nil, ErrFolderNotFound
+	}
+	return f.renameFolder(index, newName)
+}
+
+// Rename a folder of an organisation and update the paths of its children
+// Input: organisation ID, folder name, new folder name
+// Output: slice of folders, IO errors
+// Errors: Non-existent folder in the organisation, invalid new name, new name already used in the organisation
+func (f *driver) RenameFolderInOrg(orgID uuid.UUID, name string, newName string) ([]Folder, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	index := f.findFolderIndexInOrg(orgID, name)
+	if index == -1 {
+		return nil, fmt.Errorf("%w in the specified organisation", ErrFolderNotFound)
+	}
+	return f.renameFolder(index, newName)
+}
+
+// Renames the folder at an index
+// Input: index of the folder, new folder name
+// Output: slice of folders, IO errors
+// Errors: Invalid new name, new name already used in the organisation, errors from the store
+func (f *driver) renameFolder(index int, newName string) ([]Folder, error) {
+	if err := checkFolderName(newName); err != nil {
+		return nil, err
+	}
+
+	// Names are unique within an organisation, so the new name cannot be taken
+	node := f.folders[index]
+	for _, folder := range f.folders {
+		if folder.OrgId == node.OrgId && folder.Name == newName {
+			return nil, fmt.Errorf("%w in the specified organisation", ErrFolderExists)
+		}
+	}
+
+	newPath := newName
+	if parent := parentPath(node.Paths); parent != "" {
+		newPath = parent + "." + newName
+	}
+	m := Mutation{Op: OpRename, OrgId: node.OrgId, Name: newName, From: node.Paths, To: newPath}
+	events, err := f.persist(m)
+	if err != nil {
+		return nil, err
+	}
+
+	f.folders[index].Name = newName
+	f.folders[index].Paths = newPath
+	f.updateFolderPaths(node.OrgId, node.Paths+".", newPath)
+	f.publish(m, events)
+
+	return append([]Folder{}, f.folders...), nil
+}
+
+// Finds the index of the last folder with a name, matching how MoveFolder picks folders
+// Input: folder name
+// Output: index of the folder, -1 if there is none
+func (f *driver) findFolderIndex(name string) int {
+	index := -1
+	for i := range f.folders {
+		if f.folders[i].Name == name {
+			index = i
+		}
+	}
+	return index
+}
+
+// Finds the index of the last folder with a name in an organisation, matching how the other methods pick folders
+// Input: organisation ID, folder name
+// Output: index of the folder, -1 if there is none
+func (f *driver) findFolderIndexInOrg(orgID uuid.UUID, name string) int {
+	index := -1
+	for i := range f.folders {
+		if f.folders[i].OrgId == orgID && f.folders[i].Name == name {
+			index = i
+		}
+	}
+	return index
+}
@@ -0,0 +1,199 @@
+package folder_test
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_RenameFolder(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	secondaryOrgID := uuid.Must(uuid.NewV4())
+
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+		{Name: "delta", Paths: "alpha.delta", OrgId: defaultOrgID},
+		{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+	}
+
+	tests := [...]struct {
+		testName string
+		name     string
+		newName  string
+		want     []folder.Folder
+	}{
+		{
+			testName: "Rename inner folder",
+			name:     "bravo",
+			newName:  "beta",
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "beta", Paths: "alpha.beta", OrgId: defaultOrgID},
+				{Name: "charlie", Paths: "alpha.beta.charlie", OrgId: defaultOrgID},
+				{Name: "delta", Paths: "alpha.delta", OrgId: defaultOrgID},
+				{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+			},
+		},
+		{
+			testName: "Rename root folder",
+			name:     "alpha",
+			newName:  "alef",
+			want: []folder.Folder{
+				{Name: "alef", Paths: "alef", OrgId: defaultOrgID},
+				{Name: "bravo", Paths: "alef.bravo", OrgId: defaultOrgID},
+				{Name: "charlie", Paths: "alef.bravo.charlie", OrgId: defaultOrgID},
+				{Name: "delta", Paths: "alef.delta", OrgId: defaultOrgID},
+				{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+			},
+		},
+		{
+			testName: "Name used in another organisation",
+			name:     "delta",
+			newName:  "foxtrot",
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+				{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+				{Name: "foxtrot", Paths: "alpha.foxtrot", OrgId: defaultOrgID},
+				{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, d := range testDrivers {
+			t.Run(d.name+"/"+tt.testName, func(t *testing.T) {
+				f := d.new(t, example1)
+				get, err := f.RenameFolder(tt.name, tt.newName)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, get)
+			})
+		}
+	}
+}
+
+func Test_folder_RenameFolder_Error(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+	}
+
+	tests := [...]struct {
+		testName string
+		name     string
+		newName  string
+		want     string
+		is       error
+	}{
+		{
+			testName: "Folder does not exist",
+			name:     "invalid_folder",
+			newName:  "zulu",
+			want:     "folder does not exist",
+			is:       folder.ErrFolderNotFound,
+		},
+		{
+			testName: "Empty name",
+			name:     "bravo",
+			newName:  "",
+			want:     "invalid folder name: name is empty",
+			is:       folder.ErrInvalidName,
+		},
+		{
+			testName: "Name with a dot",
+			name:     "bravo",
+			newName:  "bra.vo",
+			want:     `invalid folder name "bra.vo": contains '.'`,
+			is:       folder.ErrInvalidName,
+		},
+		{
+			testName: "Name already used",
+			name:     "bravo",
+			newName:  "charlie",
+			want:     "folder already exists in the specified organisation",
+			is:       folder.ErrFolderExists,
+		},
+	}
+
+	for _, tt := range tests {
+		for _, d := range testDrivers {
+			t.Run(d.name+"/"+tt.testName, func(t *testing.T) {
+				f := d.new(t, example1)
+				_, err := f.RenameFolder(tt.name, tt.newName)
+				assert.ErrorContains(t, err, tt.want)
+				assert.ErrorIs(t, err, tt.is)
+			})
+		}
+	}
+}
+
+func Test_folder_RenameFolder_OtherOrganisation(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	secondaryOrgID := uuid.Must(uuid.NewV4())
+
+	// Both organisations have an alpha.xray path, only the renamed organisation changes
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: secondaryOrgID},
+		{Name: "xray", Paths: "alpha.xray", OrgId: secondaryOrgID},
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "xray", Paths: "alpha.xray", OrgId: defaultOrgID},
+	}
+
+	for _, d := range testDrivers {
+		t.Run(d.name, func(t *testing.T) {
+			f := d.new(t, example1)
+			_, err := f.RenameFolder("alpha", "omega")
+			assert.NoError(t, err)
+			assert.Equal(t, example1[:2], f.GetFoldersByOrgID(secondaryOrgID))
+			assert.Equal(t, []folder.Folder{
+				{Name: "omega", Paths: "omega", OrgId: defaultOrgID},
+				{Name: "xray", Paths: "omega.xray", OrgId: defaultOrgID},
+			}, f.GetFoldersByOrgID(defaultOrgID))
+		})
+	}
+}
+
+func Test_folder_RenameFolderInOrg(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	secondaryOrgID := uuid.Must(uuid.NewV4())
+
+	// The secondary organisation comes last, so RenameFolder would pick its alpha
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "xray", Paths: "alpha.xray", OrgId: defaultOrgID},
+		{Name: "alpha", Paths: "alpha", OrgId: secondaryOrgID},
+		{Name: "omega", Paths: "omega", OrgId: secondaryOrgID},
+	}
+
+	for _, d := range testDrivers {
+		t.Run(d.name, func(t *testing.T) {
+			f := d.new(t, example1)
+			_, err := f.RenameFolderInOrg(defaultOrgID, "alpha", "omega")
+			assert.NoError(t, err)
+			assert.Equal(t, []folder.Folder{
+				{Name: "omega", Paths: "omega", OrgId: defaultOrgID},
+				{Name: "xray", Paths: "omega.xray", OrgId: defaultOrgID},
+			}, f.GetFoldersByOrgID(defaultOrgID))
+			assert.Equal(t, example1[2:], f.GetFoldersByOrgID(secondaryOrgID))
+
+			_, err = f.RenameFolderInOrg(secondaryOrgID, "xray", "zulu")
+			assert.ErrorIs(t, err, folder.ErrFolderNotFound)
+			assert.ErrorContains(t, err, "folder does not exist in the specified organisation")
+		})
+	}
+}
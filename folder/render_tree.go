@@ -0,0 +1,100 @@
+package folder
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+type TreeOptions struct {
+	// MaxDepth limits how many levels are drawn below each organisation, 0 draws everything
+	MaxDepth int
+	// ASCII draws branches with plain characters for terminals without box-drawing support
+	ASCII bool
+	// Color highlights organisations, folders with children and hidden counts with ANSI escapes
+	Color bool
+}
+
+type treeGlyphs struct {
+	branch, last, pipe, space string
+}
+
+var (
+	boxGlyphs   = treeGlyphs{branch: "├── ", last: "└── ", pipe: "│   ", space: "    "}
+	asciiGlyphs = treeGlyphs{branch: "|-- ", last: "`-- ", pipe: "|   ", space: "    "}
+)
+
+const (
+	ansiBold  = "\x1b[1m"
+	ansiBlue  = "\x1b[34m"
+	ansiDim   = "\x1b[2m"
+	ansiReset = "\x1b[0m"
+)
+
+// Draws folders like the tree command, one tree per organisation:
+//
+//	c1556e17-b7c0-45a3-a6ae-9546248fb17a
+//	├── alpha
+//	│   ├── bravo
+//	│   │   └── charlie
+//	│   └── delta
+//	└── golf
+//
+// Folders whose parent is not in the set are drawn as roots with their full path.
+// Folders cut off by MaxDepth are counted next to their ancestor, e.g. "alpha (+3)".
+// Input: writer, folders, tree options
+// Output: error
+// Errors: Duplicate folders, IO errors
+func RenderTree(w io.Writer, folders []Folder, opts TreeOptions) error {
+	trees, err := buildTrees(folders)
+	if err != nil {
+		return err
+	}
+
+	glyphs := boxGlyphs
+	if opts.ASCII {
+		glyphs = asciiGlyphs
+	}
+	paint := func(code string, s string) string {
+		if !opts.Color {
+			return s
+		}
+		return code + s + ansiReset
+	}
+
+	bw := bufio.NewWriter(w)
+	var draw func(nodes []*treeNode, prefix string, depth int)
+	draw = func(nodes []*treeNode, prefix string, depth int) {
+		for i, node := range nodes {
+			branch, indent := glyphs.branch, glyphs.pipe
+			if i == len(nodes)-1 {
+				branch, indent = glyphs.last, glyphs.space
+			}
+
+			label := node.Name
+			if depth == 1 && parentPath(node.Paths) != "" {
+				label = node.Paths
+			}
+			if len(node.Children) > 0 {
+				label = paint(ansiBlue, label)
+			}
+
+			if opts.MaxDepth > 0 && depth >= opts.MaxDepth && len(node.Children) > 0 {
+				fmt.Fprintf(bw, "%s%s%s %s\n", prefix, branch, label, paint(ansiDim, fmt.Sprintf("(+%d)", node.descendants())))
+				continue
+			}
+			fmt.Fprintf(bw, "%s%s%s\n", prefix, branch, label)
+			draw(node.Children, prefix+indent, depth+1)
+		}
+	}
+
+	for i, tree := range trees {
+		if i > 0 {
+			fmt.Fprintln(bw)
+		}
+		fmt.Fprintln(bw, paint(ansiBold, tree.OrgId.String()))
+		draw(tree.Roots, "", 1)
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,86 @@
+package folder_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_RenderTree(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	secondaryOrgID := uuid.FromStringOrNil("38b9879b-f73b-4b0e-b9d9-4fc4c23643a7")
+
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+		{Name: "delta", Paths: "alpha.delta", OrgId: defaultOrgID},
+		{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+	}
+
+	tests := [...]struct {
+		testName string
+		folders  []folder.Folder
+		opts     folder.TreeOptions
+		want     string
+	}{
+		{
+			testName: "Empty folder",
+			folders:  []folder.Folder{},
+			want:     "",
+		},
+		{
+			testName: "Grouped by organisation",
+			folders:  example1,
+			want: `c1556e17-b7c0-45a3-a6ae-9546248fb17a
+├── alpha
+│   ├── bravo
+│   │   └── charlie
+│   └── delta
+└── golf
+
+38b9879b-f73b-4b0e-b9d9-4fc4c23643a7
+└── foxtrot
+`,
+		},
+		{
+			testName: "ASCII with max depth",
+			folders:  example1,
+			opts:     folder.TreeOptions{ASCII: true, MaxDepth: 2},
+			want: "c1556e17-b7c0-45a3-a6ae-9546248fb17a\n" +
+				"|-- alpha\n" +
+				"|   |-- bravo (+1)\n" +
+				"|   `-- delta\n" +
+				"`-- golf\n" +
+				"\n" +
+				"38b9879b-f73b-4b0e-b9d9-4fc4c23643a7\n" +
+				"`-- foxtrot\n",
+		},
+		{
+			testName: "Subtree drawn from its full path",
+			folders: []folder.Folder{
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+				{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+			},
+			opts: folder.TreeOptions{Color: true},
+			want: "\x1b[1mc1556e17-b7c0-45a3-a6ae-9546248fb17a\x1b[0m\n" +
+				"└── \x1b[34malpha.bravo\x1b[0m\n" +
+				"    └── charlie\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			var b strings.Builder
+			err := folder.RenderTree(&b, tt.folders, tt.opts)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, b.String())
+		})
+	}
+}
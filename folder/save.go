@@ -0,0 +1,61 @@
+package folder
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// Writes folders as indented JSON, replacing the file atomically so readers
+// never see a partially written file
+// Input: file path, folders
+// Output: error
+// Errors: JSON encoding errors, IO errors
+func SaveFolders(path string, folders []Folder) error {
+	b, err := json.MarshalIndent(folders, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return WriteFileAtomic(path, b)
+}
+
+// WriteFileAtomic writes to a temporary file in the same directory, syncs it, then renames it over the target
+// Input: file path, contents
+// Output: error
+// Errors: IO errors
+func WriteFileAtomic(path string, b []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	// Cleans up after a failure, the file is already gone after a successful rename
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	// CreateTemp uses 0600, folder files are readable like any other data file
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	// Sync the directory so the rename itself survives a crash
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
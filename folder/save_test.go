@@ -0,0 +1,48 @@
+package folder_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_SaveFolders(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "folders.json")
+	assert.NoError(t, os.WriteFile(path, []byte("stale"), 0600))
+
+	assert.NoError(t, folder.SaveFolders(path, folders))
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode())
+
+	get, err := folder.LoadFoldersFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, folders, get)
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func Test_folder_SaveFolders_Error(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "missing", "folders.json")
+	err := folder.SaveFolders(path, []folder.Folder{})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
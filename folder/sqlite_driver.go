@@ -0,0 +1,520 @@
+package folder
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gofrs/uuid"
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS folders (
+	seq    INTEGER PRIMARY KEY AUTOINCREMENT,
+	name   TEXT NOT NULL,
+	org_id TEXT NOT NULL,
+	paths  TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS folders_org_paths ON folders (org_id, paths);
+CREATE INDEX IF NOT EXISTS folders_org_name ON folders (org_id, name);
+CREATE INDEX IF NOT EXISTS folders_name ON folders (name);
+`
+
+// SQLiteDriver is a driver backed by an embedded SQLite database.
+// Rows keep their insertion order so results match the in-memory driver.
+// It also implements Store, so it can back an in-memory driver instead.
+// It is safe for concurrent use, changes are made one at a time.
+type SQLiteDriver struct {
+	db *sql.DB
+
+	// held by every change from its checks until its events are published
+	mu *sync.Mutex
+
+	// subscribers to change events
+	*broker
+
+	// passed to the observers of changes made through this driver, see as
+	origin any
+}
+
+// Opens a SQLite database file, creating the schema if needed
+// Input: path to the database file, or ":memory:" for a throwaway database
+// Output: SQLite driver, error
+// Errors: Database errors
+func OpenSQLiteDriver(path string) (*SQLiteDriver, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	// A single connection keeps ":memory:" databases alive and serialises writers
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteDriver{db: db, mu: &sync.Mutex{}, broker: &broker{}}, nil
+}
+
+// Returns a view of the driver sharing its database, whose changes reach the observers with origin
+func (d *SQLiteDriver) as(origin any) IDriver {
+	return &SQLiteDriver{db: d.db, mu: d.mu, broker: d.broker, origin: origin}
+}
+
+// Numbers and delivers the events of a change made through this driver
+func (d *SQLiteDriver) publish(m Mutation, events []Event) {
+	d.broker.publish(d.origin, m, events)
+}
+
+func (d *SQLiteDriver) Close() error {
+	return d.db.Close()
+}
+
+// Returns the bounds of every path strictly below a path,
+// '/' is the byte after '.' so the range covers exactly the "path." prefix
+func descendantRange(path string) (string, string) {
+	return path + ".", path + "/"
+}
+
+func (d *SQLiteDriver) query(query string, args ...any) ([]Folder, error) {
+	return queryFolders(d.db, query, args...)
+}
+
+// Runs a query selecting name, org_id and paths on a database or inside a transaction
+func queryFolders(q interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}, query string, args ...any) ([]Folder, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := []Folder{}
+	for rows.Next() {
+		var folder Folder
+		var orgID string
+		if err := rows.Scan(&folder.Name, &orgID, &folder.Paths); err != nil {
+			return nil, err
+		}
+		folder.OrgId = uuid.FromStringOrNil(orgID)
+		res = append(res, folder)
+	}
+	return res, rows.Err()
+}
+
+func (d *SQLiteDriver) GetFoldersByOrgID(orgID uuid.UUID) []Folder {
+	res, err := d.query(`SELECT name, org_id, paths FROM folders WHERE org_id = ? ORDER BY seq`, orgID.String())
+	if err != nil {
+		return []Folder{}
+	}
+	return res
+}
+
+// Finds the path of the first folder with a name in an organisation
+func (d *SQLiteDriver) findPath(orgID uuid.UUID, name string) (string, error) {
+	var path string
+	err := d.db.QueryRow(
+		`SELECT paths FROM folders WHERE org_id = ? AND name = ? ORDER BY seq LIMIT 1`,
+		orgID.String(), name,
+	).Scan(&path)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("%w in the specified organisation", ErrFolderNotFound)
+	}
+	return path, err
+}
+
+func (d *SQLiteDriver) GetAllChildFolders(orgID uuid.UUID, name string) ([]Folder, error) {
+	return d.GetChildFoldersToDepth(orgID, name, 0)
+}
+
+func (d *SQLiteDriver) GetChildFoldersToDepth(orgID uuid.UUID, name string, depth int) ([]Folder, error) {
+	path, err := d.findPath(orgID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	low, high := descendantRange(path)
+	if depth <= 0 {
+		return d.query(
+			`SELECT name, org_id, paths FROM folders WHERE org_id = ? AND paths > ? AND paths < ? ORDER BY seq`,
+			orgID.String(), low, high,
+		)
+	}
+	// A path's level is the number of dots in it
+	return d.query(
+		`SELECT name, org_id, paths FROM folders WHERE org_id = ? AND paths > ? AND paths < ?
+			AND length(paths) - length(replace(paths, '.', '')) <= ? ORDER BY seq`,
+		orgID.String(), low, high, strings.Count(path, ".")+depth,
+	)
+}
+
+func (d *SQLiteDriver) GetAncestorFolders(orgID uuid.UUID, name string) ([]Folder, error) {
+	path, err := d.findPath(orgID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every proper prefix of the path made of whole labels is an ancestor
+	labels := strings.Split(path, ".")
+	if len(labels) == 1 {
+		return []Folder{}, nil
+	}
+	args := []any{orgID.String()}
+	for i := 1; i < len(labels); i++ {
+		args = append(args, strings.Join(labels[:i], "."))
+	}
+	placeholders := strings.Repeat(", ?", len(args)-1)[2:]
+
+	return d.query(
+		`SELECT name, org_id, paths FROM folders WHERE org_id = ? AND paths IN (`+placeholders+`) ORDER BY length(paths), seq`,
+		args...,
+	)
+}
+
+// Looks up the last folder with a name in any organisation, matching the in-memory driver
+func (d *SQLiteDriver) findByName(name string) (Folder, bool, error) {
+	return scanFolder(d.db.QueryRow(
+		`SELECT name, org_id, paths FROM folders WHERE name = ? ORDER BY seq DESC LIMIT 1`, name,
+	))
+}
+
+// Looks up the last folder with a name in an organisation, matching the in-memory driver
+func (d *SQLiteDriver) findInOrg(orgID uuid.UUID, name string) (Folder, bool, error) {
+	return scanFolder(d.db.QueryRow(
+		`SELECT name, org_id, paths FROM folders WHERE org_id = ? AND name = ? ORDER BY seq DESC LIMIT 1`, orgID.String(), name,
+	))
+}
+
+// Reads a folder from a row of name, org_id and paths, reporting whether there was one
+func scanFolder(row *sql.Row) (Folder, bool, error) {
+	var folder Folder
+	var orgID string
+	err := row.Scan(&folder.Name, &orgID, &folder.Paths)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Folder{}, false, nil
+	} else if err != nil {
+		return Folder{}, false, err
+	}
+	folder.OrgId = uuid.FromStringOrNil(orgID)
+	return folder, true, nil
+}
+
+func (d *SQLiteDriver) MoveFolder(name string, dst string) ([]Folder, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	nodeToMove, foundSource, err := d.findByName(name)
+	if err != nil {
+		return nil, err
+	}
+	destination, foundDest, err := d.findByName(dst)
+	if err != nil {
+		return nil, err
+	}
+
+	if !foundSource {
+		return nil, fmt.Errorf("source %w", ErrFolderNotFound)
+	} else if !foundDest {
+		return nil, fmt.Errorf("destination %w", ErrFolderNotFound)
+	} else if name == dst {
+		return nil, fmt.Errorf("%w to itself", ErrInvalidMove)
+	}
+	return d.moveFolder(nodeToMove, destination)
+}
+
+func (d *SQLiteDriver) MoveFolderInOrg(orgID uuid.UUID, name string, dst string) ([]Folder, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	nodeToMove, foundSource, err := d.findInOrg(orgID, name)
+	if err != nil {
+		return nil, err
+	}
+	destination, foundDest, err := d.findInOrg(orgID, dst)
+	if err != nil {
+		return nil, err
+	}
+
+	if !foundSource {
+		return nil, fmt.Errorf("source %w in the specified organisation", ErrFolderNotFound)
+	} else if !foundDest {
+		return nil, fmt.Errorf("destination %w in the specified organisation", ErrFolderNotFound)
+	} else if name == dst {
+		return nil, fmt.Errorf("%w to itself", ErrInvalidMove)
+	}
+	return d.moveFolder(nodeToMove, destination)
+}
+
+// Moves a folder found by one of the named methods into another
+func (d *SQLiteDriver) moveFolder(nodeToMove Folder, destination Folder) ([]Folder, error) {
+	if nodeToMove.OrgId != destination.OrgId {
+		return nil, fmt.Errorf("%w to a different organisation", ErrInvalidMove)
+	} else if strings.HasPrefix(destination.Paths, nodeToMove.Paths+".") {
+		return nil, fmt.Errorf("%w to a child of itself", ErrInvalidMove)
+	}
+
+	m := Mutation{
+		Op:    OpMove,
+		OrgId: nodeToMove.OrgId,
+		Name:  nodeToMove.Name,
+		From:  nodeToMove.Paths,
+		To:    destination.Paths + "." + nodeToMove.Name,
+	}
+	if err := d.apply(m); err != nil {
+		return nil, err
+	}
+
+	return d.Load()
+}
+
+func (d *SQLiteDriver) RenameFolder(name string, newName string) ([]Folder, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	node, found, err := d.findByName(name)
+	if err != nil {
+		return nil, err
+	} else if !found {
+		return nil, ErrFolderNotFound
+	}
+	return d.renameFolder(node, newName)
+}
+
+func (d *SQLiteDriver) RenameFolderInOrg(orgID uuid.UUID, name string, newName string) ([]Folder, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	node, found, err := d.findInOrg(orgID, name)
+	if err != nil {
+		return nil, err
+	} else if !found {
+		return nil, fmt.Errorf("%w in the specified organisation", ErrFolderNotFound)
+	}
+	return d.renameFolder(node, newName)
+}
+
+// Renames a folder found by one of the named methods
+func (d *SQLiteDriver) renameFolder(node Folder, newName string) ([]Folder, error) {
+	if err := checkFolderName(newName); err != nil {
+		return nil, err
+	}
+
+	var taken bool
+	err := d.db.QueryRow(
+		`SELECT EXISTS (SELECT 1 FROM folders WHERE org_id = ? AND name = ?)`, node.OrgId.String(), newName,
+	).Scan(&taken)
+	if err != nil {
+		return nil, err
+	} else if taken {
+		return nil, fmt.Errorf("%w in the specified organisation", ErrFolderExists)
+	}
+
+	newPath := newName
+	if parent := parentPath(node.Paths); parent != "" {
+		newPath = parent + "." + newName
+	}
+	if err := d.apply(Mutation{Op: OpRename, OrgId: node.OrgId, Name: newName, From: node.Paths, To: newPath}); err != nil {
+		return nil, err
+	}
+
+	return d.Load()
+}
+
+func (d *SQLiteDriver) DeleteFolder(name string) ([]Folder, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	node, found, err := d.findByName(name)
+	if err != nil {
+		return nil, err
+	} else if !found {
+		return nil, ErrFolderNotFound
+	}
+	return d.deleteFolder(node)
+}
+
+func (d *SQLiteDriver) DeleteFolderInOrg(orgID uuid.UUID, name string) ([]Folder, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	node, found, err := d.findInOrg(orgID, name)
+	if err != nil {
+		return nil, err
+	} else if !found {
+		return nil, fmt.Errorf("%w in the specified organisation", ErrFolderNotFound)
+	}
+	return d.deleteFolder(node)
+}
+
+// Deletes a folder found by one of the named methods and its children
+func (d *SQLiteDriver) deleteFolder(node Folder) ([]Folder, error) {
+	if err := d.apply(Mutation{Op: OpDelete, OrgId: node.OrgId, Name: node.Name, From: node.Paths}); err != nil {
+		return nil, err
+	}
+
+	return d.Load()
+}
+
+func (d *SQLiteDriver) CreateFolder(orgID uuid.UUID, name string, parent string) ([]Folder, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := checkFolderName(name); err != nil {
+		return nil, err
+	}
+
+	var taken bool
+	err := d.db.QueryRow(
+		`SELECT EXISTS (SELECT 1 FROM folders WHERE org_id = ? AND name = ?)`, orgID.String(), name,
+	).Scan(&taken)
+	if err != nil {
+		return nil, err
+	} else if taken {
+		return nil, fmt.Errorf("%w in the specified organisation", ErrFolderExists)
+	}
+
+	path := name
+	if parent != "" {
+		base, err := d.findPath(orgID, parent)
+		if err != nil {
+			return nil, err
+		}
+		path = base + "." + name
+	}
+	if err := d.apply(Mutation{Op: OpCreate, OrgId: orgID, Name: name, To: path}); err != nil {
+		return nil, err
+	}
+
+	return d.Load()
+}
+
+// Load returns every folder in insertion order.
+func (d *SQLiteDriver) Load() ([]Folder, error) {
+	return d.query(`SELECT name, org_id, paths FROM folders ORDER BY seq`)
+}
+
+// Save replaces every row with the given folders.
+func (d *SQLiteDriver) Save(folders []Folder) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM folders`); err != nil {
+		return err
+	}
+	if err := insertFolders(tx, folders); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Applies a single change to the affected rows in one transaction
+// Input: mutation
+// Output: error
+// Errors: Unknown operation, missing folder, folder already exists, database errors
+func (d *SQLiteDriver) Apply(m Mutation) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.apply(m)
+}
+
+// Applies a mutation, the caller holds the lock
+func (d *SQLiteDriver) apply(m Mutation) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	orgID := m.OrgId.String()
+	if m.Op == OpCreate {
+		if found, err := pathExists(tx, orgID, m.To); err != nil {
+			return err
+		} else if found {
+			return ErrFolderExists
+		}
+		if err := insertFolders(tx, []Folder{{Name: m.Name, OrgId: m.OrgId, Paths: m.To}}); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		d.publish(m, mutationEvents(nil, m))
+		return nil
+	}
+
+	if found, err := pathExists(tx, orgID, m.From); err != nil {
+		return err
+	} else if !found {
+		return fmt.Errorf("%w in the specified organisation", ErrFolderNotFound)
+	}
+
+	// The subtree before the change, to report every folder it touches
+	low, high := descendantRange(m.From)
+	subtree, err := queryFolders(tx,
+		`SELECT name, org_id, paths FROM folders WHERE org_id = ? AND (paths = ? OR (paths > ? AND paths < ?)) ORDER BY seq`,
+		orgID, m.From, low, high,
+	)
+	if err != nil {
+		return err
+	}
+
+	switch m.Op {
+	case OpDelete:
+		_, err = tx.Exec(
+			`DELETE FROM folders WHERE org_id = ? AND (paths = ? OR (paths > ? AND paths < ?))`,
+			orgID, m.From, low, high,
+		)
+	case OpMove, OpRename:
+		_, err = tx.Exec(
+			`UPDATE folders SET paths = ? || substr(paths, length(?) + 1) WHERE org_id = ? AND paths > ? AND paths < ?`,
+			m.To, m.From, orgID, low, high,
+		)
+		if err == nil {
+			_, err = tx.Exec(`UPDATE folders SET name = ?, paths = ? WHERE org_id = ? AND paths = ?`, m.Name, m.To, orgID, m.From)
+		}
+	default:
+		return errors.New("unknown operation: " + string(m.Op))
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	d.publish(m, mutationEvents(subtree, m))
+	return nil
+}
+
+func pathExists(tx *sql.Tx, orgID string, path string) (bool, error) {
+	var found bool
+	err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM folders WHERE org_id = ? AND paths = ?)`, orgID, path).Scan(&found)
+	return found, err
+}
+
+func insertFolders(tx *sql.Tx, folders []Folder) error {
+	stmt, err := tx.Prepare(`INSERT INTO folders (name, org_id, paths) VALUES (?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, folder := range folders {
+		if _, err := stmt.Exec(folder.Name, folder.OrgId.String(), folder.Paths); err != nil {
+			return err
+		}
+	}
+	return nil
+}
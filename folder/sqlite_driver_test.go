@@ -0,0 +1,129 @@
+package folder_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// Every driver implementation runs through the same behavioural tests.
+// Folders are copied so a driver that mutates them in place cannot leak into the next case.
+var testDrivers = [...]struct {
+	name string
+	new  func(t *testing.T, folders []folder.Folder) folder.IDriver
+}{
+	{
+		name: "Memory",
+		new: func(t *testing.T, folders []folder.Folder) folder.IDriver {
+			return folder.NewDriver(append([]folder.Folder{}, folders...))
+		},
+	},
+	{
+		name: "SQLite",
+		new: func(t *testing.T, folders []folder.Folder) folder.IDriver {
+			d, err := folder.OpenSQLiteDriver(":memory:")
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { d.Close() })
+			if err := d.Save(folders); err != nil {
+				t.Fatal(err)
+			}
+			return d
+		},
+	},
+}
+
+func Test_folder_GetAncestorFolders(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	secondaryOrgID := uuid.Must(uuid.NewV4())
+
+	example1 := []folder.Folder{
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "delta", Paths: "alpha.delta", OrgId: defaultOrgID},
+		{Name: "alphaa", Paths: "alphaa", OrgId: defaultOrgID},
+		{Name: "alpha", Paths: "alpha", OrgId: secondaryOrgID},
+	}
+
+	tests := [...]struct {
+		testName string
+		name     string
+		orgID    uuid.UUID
+		want     []folder.Folder
+		err      string
+	}{
+		{
+			testName: "Root folder",
+			name:     "alpha",
+			orgID:    defaultOrgID,
+			want:     []folder.Folder{},
+		},
+		{
+			testName: "Nested folder ordered from the root",
+			name:     "charlie",
+			orgID:    defaultOrgID,
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+			},
+		},
+		{
+			testName: "Folder does not exist in specified organisation",
+			name:     "charlie",
+			orgID:    secondaryOrgID,
+			err:      "folder does not exist in the specified organisation",
+		},
+	}
+
+	for _, tt := range tests {
+		for _, d := range testDrivers {
+			t.Run(d.name+"/"+tt.testName, func(t *testing.T) {
+				f := d.new(t, example1)
+				get, err := f.GetAncestorFolders(tt.orgID, tt.name)
+				if tt.err != "" {
+					assert.ErrorContains(t, err, tt.err)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, get)
+			})
+		}
+	}
+}
+
+func Test_folder_SQLiteDriver_Reopen(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	path := filepath.Join(t.TempDir(), "folders.db")
+
+	d, err := folder.OpenSQLiteDriver(path)
+	assert.NoError(t, err)
+	err = d.Save([]folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+	})
+	assert.NoError(t, err)
+	_, err = d.MoveFolder("bravo", "golf")
+	assert.NoError(t, err)
+	assert.NoError(t, d.Close())
+
+	reopened, err := folder.OpenSQLiteDriver(path)
+	assert.NoError(t, err)
+	defer reopened.Close()
+	get, err := reopened.GetAllChildFolders(defaultOrgID, "golf")
+	assert.NoError(t, err)
+	assert.Equal(t, []folder.Folder{
+		{Name: "bravo", Paths: "golf.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "golf.bravo.charlie", OrgId: defaultOrgID},
+	}, get)
+}
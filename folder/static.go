@@ -1,11 +1,10 @@
 package folder
 
 import (
+	"bytes"
+	_ "embed"
 	"encoding/json"
 	"fmt"
-	"io"
-	"io/fs"
-	"os"
 	"path/filepath"
 	"runtime"
 
@@ -103,44 +102,32 @@ func PrettyPrint(b interface{}) {
 	fmt.Print(string(s))
 }
 
-func GetSampleData() []Folder {
-	_, filename, _, _ := runtime.Caller(0)
-	fmt.Println(filename)
-	basePath := filepath.Dir(filename)
-	filePath := filepath.Join(basePath, "sample.json")
+// sample.json is embedded so compiled binaries do not need the source tree on disk,
+// regenerate the binary after WriteSampleData to pick up new sample data
+//
+//go:embed sample.json
+var sampleData []byte
 
-	fmt.Println(filePath)
-
-	file, err := os.Open(filePath)
+func GetSampleData() []Folder {
+	folders, err := LoadFolders(bytes.NewReader(sampleData))
 	if err != nil {
 		panic(err)
 	}
-	defer file.Close()
 
-	jsonByte, err := io.ReadAll(file)
-	if err != nil {
-		panic(err)
-	}
+	return folders
+}
 
-	folders := []Folder{}
-	err = json.Unmarshal(jsonByte, &folders)
+// Overwrites sample.json in the source tree, use SaveFolders to write anywhere else
+func WriteSampleData(data interface{}) {
+	b, err := json.MarshalIndent(data, "", "\t")
 	if err != nil {
 		panic(err)
 	}
 
-	return folders
-}
-
-func WriteSampleData(data interface{}) {
-	b := MarshalJson(data)
 	_, filename, _, _ := runtime.Caller(0)
-	fmt.Println(filename)
-	basePath := filepath.Dir(filename)
-	filePath := filepath.Join(basePath, "sample.json")
-
-	fmt.Println(filePath)
+	filePath := filepath.Join(filepath.Dir(filename), "sample.json")
 
-	err := os.WriteFile(filePath, b, fs.ModeType)
+	err = WriteFileAtomic(filePath, b)
 	if err != nil {
 		panic(err)
 	}
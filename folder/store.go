@@ -0,0 +1,202 @@
+package folder
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+
+	"github.com/gofrs/uuid"
+)
+
+// Op is the kind of change made to a folder and its subtree.
+type Op string
+
+const (
+	OpCreate Op = "create"
+	OpMove   Op = "move"
+	OpRename Op = "rename"
+	OpDelete Op = "delete"
+)
+
+// Mutation describes a single change made through the driver.
+// From is the path of the folder before the change and To is its path afterwards,
+// creates only set To and deletes only set From.
+type Mutation struct {
+	Op    Op        `json:"op"`
+	OrgId uuid.UUID `json:"org_id"`
+	Name  string    `json:"name"`
+	From  string    `json:"from,omitempty"`
+	To    string    `json:"to,omitempty"`
+}
+
+// Store persists the folders held by a driver.
+type Store interface {
+	// Load returns every folder held by the store.
+	Load() ([]Folder, error)
+	// Save replaces the contents of the store with the given folders.
+	Save(folders []Folder) error
+	// Apply records a single change made by the driver.
+	Apply(m Mutation) error
+}
+
+// Applies a mutation to a folder set, returning the updated copy
+// Input: folders, mutation
+// Output: new slice of folders, error
+// Errors: Unknown operation, missing folder, folder already exists
+func applyMutation(folders []Folder, m Mutation) ([]Folder, error) {
+	res := make([]Folder, 0, len(folders)+1)
+
+	if m.Op == OpCreate {
+		for _, folder := range folders {
+			if folder.OrgId == m.OrgId && folder.Paths == m.To {
+				return nil, ErrFolderExists
+			}
+		}
+		res = append(res, folders...)
+		return append(res, Folder{Name: m.Name, OrgId: m.OrgId, Paths: m.To}), nil
+	}
+
+	found := false
+	for _, folder := range folders {
+		inSubtree := folder.OrgId == m.OrgId &&
+			(folder.Paths == m.From || strings.HasPrefix(folder.Paths, m.From+"."))
+		if !inSubtree {
+			res = append(res, folder)
+			continue
+		}
+		if folder.Paths == m.From {
+			found = true
+		}
+
+		switch m.Op {
+		case OpDelete:
+			// Drop the folder and all of its children
+		case OpMove, OpRename:
+			if folder.Paths == m.From {
+				folder.Name = m.Name
+			}
+			folder.Paths = m.To + strings.TrimPrefix(folder.Paths, m.From)
+			res = append(res, folder)
+		default:
+			return nil, errors.New("unknown operation: " + string(m.Op))
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("%w in the specified organisation", ErrFolderNotFound)
+	}
+
+	return res, nil
+}
+
+// MemoryStore keeps folders in memory only.
+type MemoryStore struct {
+	mu      sync.Mutex
+	folders []Folder
+}
+
+func NewMemoryStore(folders []Folder) *MemoryStore {
+	return &MemoryStore{folders: append([]Folder{}, folders...)}
+}
+
+func (s *MemoryStore) Load() ([]Folder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Folder{}, s.folders...), nil
+}
+
+func (s *MemoryStore) Save(folders []Folder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.folders = append([]Folder{}, folders...)
+	return nil
+}
+
+func (s *MemoryStore) Apply(m Mutation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	folders, err := applyMutation(s.folders, m)
+	if err != nil {
+		return err
+	}
+	s.folders = folders
+	return nil
+}
+
+// FileStore keeps folders in a JSON file in the same format as sample.json.
+// Every change atomically rewrites the whole file.
+type FileStore struct {
+	mu      sync.Mutex
+	path    string
+	folders []Folder
+	loaded  bool
+}
+
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Reads the folders from the file, a missing file is treated as an empty store
+// Input: None
+// Output: slice of folders, error
+// Errors: IO errors, invalid JSON
+func (s *FileStore) Load() ([]Folder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return append([]Folder{}, s.folders...), nil
+}
+
+func (s *FileStore) Save(folders []Folder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.save(folders)
+}
+
+func (s *FileStore) Apply(m Mutation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return err
+	}
+	folders, err := applyMutation(s.folders, m)
+	if err != nil {
+		return err
+	}
+	return s.save(folders)
+}
+
+func (s *FileStore) load() error {
+	if s.loaded {
+		return nil
+	}
+
+	folders, err := LoadFoldersFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		folders = []Folder{}
+	} else if err != nil {
+		return err
+	}
+	s.folders = folders
+	s.loaded = true
+	return nil
+}
+
+func (s *FileStore) save(folders []Folder) error {
+	if err := SaveFolders(s.path, folders); err != nil {
+		return err
+	}
+	s.folders = append([]Folder{}, folders...)
+	s.loaded = true
+	return nil
+}
@@ -0,0 +1,137 @@
+package folder_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_Store(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+
+	stores := map[string]func(t *testing.T, folders []folder.Folder) folder.Store{
+		"Memory store": func(t *testing.T, folders []folder.Folder) folder.Store {
+			return folder.NewMemoryStore(folders)
+		},
+		"File store": func(t *testing.T, folders []folder.Folder) folder.Store {
+			s := folder.NewFileStore(filepath.Join(t.TempDir(), "folders.json"))
+			assert.NoError(t, s.Save(folders))
+			return s
+		},
+	}
+
+	tests := [...]struct {
+		testName string
+		change   folder.Mutation
+		want     []folder.Folder
+		err      string
+	}{
+		{
+			testName: "Move subtree",
+			change:   folder.Mutation{Op: folder.OpMove, OrgId: defaultOrgID, Name: "bravo", From: "alpha.bravo", To: "golf.bravo"},
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "bravo", Paths: "golf.bravo", OrgId: defaultOrgID},
+				{Name: "charlie", Paths: "golf.bravo.charlie", OrgId: defaultOrgID},
+				{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+			},
+		},
+		{
+			testName: "Rename folder",
+			change:   folder.Mutation{Op: folder.OpRename, OrgId: defaultOrgID, Name: "beta", From: "alpha.bravo", To: "alpha.beta"},
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "beta", Paths: "alpha.beta", OrgId: defaultOrgID},
+				{Name: "charlie", Paths: "alpha.beta.charlie", OrgId: defaultOrgID},
+				{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+			},
+		},
+		{
+			testName: "Delete subtree",
+			change:   folder.Mutation{Op: folder.OpDelete, OrgId: defaultOrgID, Name: "bravo", From: "alpha.bravo"},
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+			},
+		},
+		{
+			testName: "Create folder",
+			change:   folder.Mutation{Op: folder.OpCreate, OrgId: defaultOrgID, Name: "hotel", To: "golf.hotel"},
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+				{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+				{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+				{Name: "hotel", Paths: "golf.hotel", OrgId: defaultOrgID},
+			},
+		},
+		{
+			testName: "Create existing folder",
+			change:   folder.Mutation{Op: folder.OpCreate, OrgId: defaultOrgID, Name: "golf", To: "golf"},
+			err:      "folder already exists",
+		},
+		{
+			testName: "Move missing folder",
+			change:   folder.Mutation{Op: folder.OpMove, OrgId: defaultOrgID, Name: "zulu", From: "zulu", To: "golf.zulu"},
+			err:      "folder does not exist in the specified organisation",
+		},
+	}
+
+	for storeName, newStore := range stores {
+		for _, tt := range tests {
+			t.Run(storeName+"/"+tt.testName, func(t *testing.T) {
+				s := newStore(t, []folder.Folder{
+					{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+					{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+					{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+					{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+				})
+
+				err := s.Apply(tt.change)
+				if tt.err != "" {
+					assert.ErrorContains(t, err, tt.err)
+					return
+				}
+				assert.NoError(t, err)
+
+				get, err := s.Load()
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, get)
+			})
+		}
+	}
+}
+
+func Test_folder_NewDriverWithStore(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	path := filepath.Join(t.TempDir(), "folders.json")
+
+	store := folder.NewFileStore(path)
+	err := store.Save([]folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+	})
+	assert.NoError(t, err)
+
+	f, err := folder.NewDriverWithStore(store)
+	assert.NoError(t, err)
+	_, err = f.MoveFolder("bravo", "golf")
+	assert.NoError(t, err)
+
+	// A new driver over the same file should see the move
+	restarted, err := folder.NewDriverWithStore(folder.NewFileStore(path))
+	assert.NoError(t, err)
+	get, err := restarted.GetAllChildFolders(defaultOrgID, "golf")
+	assert.NoError(t, err)
+	assert.Equal(t, []folder.Folder{
+		{Name: "bravo", Paths: "golf.bravo", OrgId: defaultOrgID},
+	}, get)
+}
@@ -0,0 +1,114 @@
+package folder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/gofrs/uuid"
+)
+
+// Encodes folders as TOML with one table per folder, keyed by organisation then folder names:
+//
+//	[c1556e17-b7c0-45a3-a6ae-9546248fb17a]
+//	[c1556e17-b7c0-45a3-a6ae-9546248fb17a.alpha]
+//	[c1556e17-b7c0-45a3-a6ae-9546248fb17a.alpha.bravo]
+//
+// Input: folders
+// Output: TOML document, error
+// Errors: Duplicate folders, folders missing their parent, names not matching their path
+func MarshalTOMLTree(folders []Folder) ([]byte, error) {
+	trees, err := buildTrees(folders)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNestedTrees(trees); err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	var write func(prefix string, nodes []*treeNode)
+	write = func(prefix string, nodes []*treeNode) {
+		for _, node := range nodes {
+			key := prefix + "." + tomlKey(node.Name)
+			fmt.Fprintf(&b, "[%s]\n", key)
+			write(key, node.Children)
+		}
+	}
+	for _, tree := range trees {
+		key := tomlKey(tree.OrgId.String())
+		fmt.Fprintf(&b, "[%s]\n", key)
+		write(key, tree.Roots)
+	}
+	return []byte(b.String()), nil
+}
+
+// Quotes a key unless it is a valid TOML bare key
+func tomlKey(key string) string {
+	bare := key != ""
+	for _, c := range key {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			bare = false
+			break
+		}
+	}
+	if bare {
+		return key
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, c := range key {
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(c)
+		case c < 0x20 || c == 0x7f:
+			fmt.Fprintf(&b, "\\u%04X", c)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// Decodes a TOML document written by MarshalTOMLTree into flat folders, parents before children
+// Tables for parent folders may be left out, they are created when a child needs them.
+// Input: TOML document
+// Output: slice of folders, error
+// Errors: Invalid TOML, keys that are not tables, invalid organisation IDs, invalid folder names
+func UnmarshalTOMLTree(b []byte) ([]Folder, error) {
+	var doc map[string]any
+	meta, err := toml.Decode(string(b), &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	folders := []Folder{}
+	seen := map[string]bool{}
+	for _, key := range meta.Keys() {
+		if meta.Type(key...) != "Hash" {
+			return nil, fmt.Errorf("key %s: expected a table", key)
+		}
+
+		orgID, err := uuid.FromString(key[0])
+		if err != nil {
+			return nil, fmt.Errorf("key %s: invalid organisation ID %q", key, key[0])
+		}
+
+		// Add the folder along with any ancestors not declared before it
+		for i := 1; i < len(key); i++ {
+			if err := checkFolderName(key[i]); err != nil {
+				return nil, fmt.Errorf("key %s: %w", key, err)
+			}
+			paths := strings.Join(key[1:i+1], ".")
+			if seen[key[0]+"/"+paths] {
+				continue
+			}
+			seen[key[0]+"/"+paths] = true
+			folders = append(folders, Folder{Name: key[i], OrgId: orgID, Paths: paths})
+		}
+	}
+	return folders, nil
+}
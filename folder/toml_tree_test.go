@@ -0,0 +1,78 @@
+package folder_test
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_TOMLTree(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bob's docs", Paths: "alpha.bob's docs", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "alpha.bob's docs.charlie", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+	}
+
+	b, err := folder.MarshalTOMLTree(folders)
+	assert.NoError(t, err)
+	assert.Equal(t, `[c1556e17-b7c0-45a3-a6ae-9546248fb17a]
+[c1556e17-b7c0-45a3-a6ae-9546248fb17a.alpha]
+[c1556e17-b7c0-45a3-a6ae-9546248fb17a.alpha."bob's docs"]
+[c1556e17-b7c0-45a3-a6ae-9546248fb17a.alpha."bob's docs".charlie]
+[c1556e17-b7c0-45a3-a6ae-9546248fb17a.golf]
+`, string(b))
+
+	get, err := folder.UnmarshalTOMLTree(b)
+	assert.NoError(t, err)
+	assert.Equal(t, folders, get)
+}
+
+func Test_folder_UnmarshalTOMLTree(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+
+	t.Run("Implicit parent tables", func(t *testing.T) {
+		get, err := folder.UnmarshalTOMLTree([]byte("[" + folder.DefaultOrgID + ".alpha.bravo]\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, []folder.Folder{
+			{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+			{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		}, get)
+	})
+
+	tests := [...]struct {
+		testName string
+		input    string
+		want     string
+	}{
+		{
+			testName: "Value instead of table",
+			input:    "[" + folder.DefaultOrgID + "]\nalpha = 1\n",
+			want:     "expected a table",
+		},
+		{
+			testName: "Invalid organisation ID",
+			input:    "[org.alpha]\n",
+			want:     `invalid organisation ID "org"`,
+		},
+		{
+			testName: "Folder name with a dot",
+			input:    "[" + folder.DefaultOrgID + ".\"al.pha\"]\n",
+			want:     `invalid folder name "al.pha": contains '.'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			_, err := folder.UnmarshalTOMLTree([]byte(tt.input))
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
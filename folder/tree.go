@@ -0,0 +1,102 @@
+package folder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+// treeNode is a folder with its children in the order they appear in the folder set.
+type treeNode struct {
+	Folder
+	Children []*treeNode
+}
+
+// orgTree holds the root folders of one organisation.
+type orgTree struct {
+	OrgId uuid.UUID
+	Roots []*treeNode
+}
+
+// Depth of a folder, roots are at depth 1
+func (n *treeNode) depth() int {
+	return strings.Count(n.Paths, ".") + 1
+}
+
+// Number of folders below a node
+func (n *treeNode) descendants() int {
+	count := 0
+	for _, child := range n.Children {
+		count += 1 + child.descendants()
+	}
+	return count
+}
+
+// Label of the folder in its path, which matches the name for well-formed folders
+func pathLabel(paths string) string {
+	return paths[strings.LastIndex(paths, ".")+1:]
+}
+
+// Path of the parent folder, empty for a root folder
+func parentPath(paths string) string {
+	i := strings.LastIndex(paths, ".")
+	if i == -1 {
+		return ""
+	}
+	return paths[:i]
+}
+
+// Checks a name can be used as a label in a folder path
+func checkFolderName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: name is empty", ErrInvalidName)
+	} else if strings.Contains(name, ".") {
+		return fmt.Errorf("%w %q: contains '.'", ErrInvalidName, name)
+	}
+	return nil
+}
+
+// Groups flat folders into one tree per organisation, in order of first appearance
+// Folders whose parent is not in the set become roots, so partial results such as
+// a subtree can still be shown.
+// Input: folders
+// Output: trees, error
+// Errors: Two folders with the same path in one organisation
+func buildTrees(folders []Folder) ([]*orgTree, error) {
+	type key struct {
+		orgID uuid.UUID
+		paths string
+	}
+
+	nodes := make(map[key]*treeNode, len(folders))
+	ordered := make([]*treeNode, 0, len(folders))
+	for _, folder := range folders {
+		k := key{folder.OrgId, folder.Paths}
+		if _, ok := nodes[k]; ok {
+			return nil, fmt.Errorf("duplicate folder %q in organisation %s", folder.Paths, folder.OrgId)
+		}
+		node := &treeNode{Folder: folder}
+		nodes[k] = node
+		ordered = append(ordered, node)
+	}
+
+	trees := []*orgTree{}
+	byOrg := map[uuid.UUID]*orgTree{}
+	for _, node := range ordered {
+		if parent, ok := nodes[key{node.OrgId, parentPath(node.Paths)}]; ok {
+			parent.Children = append(parent.Children, node)
+			continue
+		}
+
+		tree, ok := byOrg[node.OrgId]
+		if !ok {
+			tree = &orgTree{OrgId: node.OrgId}
+			byOrg[node.OrgId] = tree
+			trees = append(trees, tree)
+		}
+		tree.Roots = append(tree.Roots, node)
+	}
+
+	return trees, nil
+}
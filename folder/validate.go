@@ -0,0 +1,56 @@
+package folder
+
+import (
+	"fmt"
+
+	"github.com/gofrs/uuid"
+)
+
+// Checks a folder set is a well-formed forest that the drivers can work with
+// Every problem is reported rather than stopping at the first one.
+// Input: folders
+// Output: slice of problems, empty when the folders are valid
+// Errors: Missing organisation, invalid names, names not matching their path,
+// duplicate paths or names within an organisation, folders missing their parent
+func ValidateFolders(folders []Folder) []error {
+	type key struct {
+		orgID uuid.UUID
+		value string
+	}
+
+	problems := []error{}
+	paths := map[key]bool{}
+	names := map[key]bool{}
+	for _, folder := range folders {
+		paths[key{folder.OrgId, folder.Paths}] = true
+	}
+
+	seenPaths := map[key]bool{}
+	for i, folder := range folders {
+		if folder.OrgId == uuid.Nil {
+			problems = append(problems, fmt.Errorf("folder %d %q: missing org_id", i, folder.Paths))
+		}
+		if err := checkFolderName(folder.Name); err != nil {
+			problems = append(problems, fmt.Errorf("folder %d %q: %w", i, folder.Paths, err))
+		} else if folder.Name != pathLabel(folder.Paths) {
+			problems = append(problems, fmt.Errorf("folder %d %q: name %q does not match its path", i, folder.Paths, folder.Name))
+		}
+
+		if seenPaths[key{folder.OrgId, folder.Paths}] {
+			problems = append(problems, fmt.Errorf("folder %d %q: duplicate path in organisation %s", i, folder.Paths, folder.OrgId))
+		}
+		seenPaths[key{folder.OrgId, folder.Paths}] = true
+
+		// Lookups by name pick one folder, so names must be unique within an organisation
+		if names[key{folder.OrgId, folder.Name}] {
+			problems = append(problems, fmt.Errorf("folder %d %q: duplicate name %q in organisation %s", i, folder.Paths, folder.Name, folder.OrgId))
+		}
+		names[key{folder.OrgId, folder.Name}] = true
+
+		if parent := parentPath(folder.Paths); parent != "" && !paths[key{folder.OrgId, parent}] {
+			problems = append(problems, fmt.Errorf("folder %d %q: parent %q does not exist", i, folder.Paths, parent))
+		}
+	}
+
+	return problems
+}
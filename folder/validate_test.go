@@ -0,0 +1,61 @@
+package folder_test
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_ValidateFolders(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	secondaryOrgID := uuid.Must(uuid.NewV4())
+
+	tests := [...]struct {
+		testName string
+		folders  []folder.Folder
+		want     []string
+	}{
+		{
+			testName: "Valid folders",
+			folders: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+				{Name: "alpha", Paths: "alpha", OrgId: secondaryOrgID},
+			},
+			want: []string{},
+		},
+		{
+			testName: "Every problem reported",
+			folders: []folder.Folder{
+				{Name: "alpha", Paths: "alpha"},
+				{Name: "bravo", Paths: "alpha.charlie", OrgId: defaultOrgID},
+				{Name: "delta", Paths: "alpha.delta", OrgId: defaultOrgID},
+				{Name: "delta", Paths: "delta", OrgId: defaultOrgID},
+				{Name: "delta", Paths: "delta", OrgId: defaultOrgID},
+			},
+			want: []string{
+				`folder 0 "alpha": missing org_id`,
+				`folder 1 "alpha.charlie": name "bravo" does not match its path`,
+				`folder 1 "alpha.charlie": parent "alpha" does not exist`,
+				`folder 2 "alpha.delta": parent "alpha" does not exist`,
+				`folder 3 "delta": duplicate name "delta" in organisation ` + folder.DefaultOrgID,
+				`folder 4 "delta": duplicate path in organisation ` + folder.DefaultOrgID,
+				`folder 4 "delta": duplicate name "delta" in organisation ` + folder.DefaultOrgID,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			get := []string{}
+			for _, err := range folder.ValidateFolders(tt.folders) {
+				get = append(get, err.Error())
+			}
+			assert.Equal(t, tt.want, get)
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package folder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Folders per chunk of a snapshot, a change copies only the chunks holding folders it touches
+const snapshotChunk = 64
+
+// VersionLimit bounds the versions kept, the oldest are forgotten first.
+// Zero fields are unbounded.
+type VersionLimit struct {
+	// Versions is the number of versions kept, including the latest
+	Versions int
+	// Age is how long a version is kept after it was replaced
+	Age time.Duration
+}
+
+// Versions keeps an immutable snapshot of a driver's folders after every change.
+// Snapshots share the chunks of folders a change did not touch, so each version costs
+// roughly the folders it changed rather than a copy of every folder.
+// It is safe to query while the driver changes.
+type Versions struct {
+	limit VersionLimit
+
+	mu       sync.RWMutex
+	versions []*Snapshot
+}
+
+// Snapshot is the folder set as of one version, it never changes.
+type Snapshot struct {
+	// Version is 0 when the driver started being versioned and increases by one for every change
+	Version uint64
+	// Time the version was made
+	Time time.Time
+
+	// folders of each organisation in driver order
+	orgs map[uuid.UUID][][]Folder
+}
+
+// Starts versioning the folders of a driver
+// Input: driver from this package, limit
+// Output: versions, error
+// Errors: Driver that does not publish its changes, errors loading the folders
+func NewVersions(d IDriver, limit VersionLimit) (*Versions, error) {
+	driver, ok := d.(interface {
+		Load() ([]Folder, error)
+		observe(fn func(origin any, m Mutation, events []Event))
+	})
+	if !ok {
+		return nil, fmt.Errorf("cannot version a %T", d)
+	}
+
+	folders, err := driver.Load()
+	if err != nil {
+		return nil, err
+	}
+	first := &Snapshot{Time: time.Now(), orgs: map[uuid.UUID][][]Folder{}}
+	for _, folder := range folders {
+		first.orgs[folder.OrgId] = appendChunked(first.orgs[folder.OrgId], folder)
+	}
+
+	v := &Versions{limit: limit, versions: []*Snapshot{first}}
+	driver.observe(v.record)
+	return v, nil
+}
+
+// Called by the driver after every change
+func (v *Versions) record(_ any, m Mutation, events []Event) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	latest := v.versions[len(v.versions)-1]
+	next := &Snapshot{Version: latest.Version + 1, Time: time.Now(), orgs: make(map[uuid.UUID][][]Folder, len(latest.orgs))}
+	for orgID, chunks := range latest.orgs {
+		next.orgs[orgID] = chunks
+	}
+	next.orgs[m.OrgId] = applyChunked(latest.orgs[m.OrgId], m)
+	if len(next.orgs[m.OrgId]) == 0 {
+		delete(next.orgs, m.OrgId)
+	}
+	v.versions = append(v.versions, next)
+
+	// Forget versions replaced long enough ago, a version is replaced when the next one is made
+	drop := 0
+	if v.limit.Versions > 0 && len(v.versions) > v.limit.Versions {
+		drop = len(v.versions) - v.limit.Versions
+	}
+	for v.limit.Age > 0 && drop < len(v.versions)-1 && next.Time.Sub(v.versions[drop+1].Time) > v.limit.Age {
+		drop++
+	}
+	if drop > 0 {
+		v.versions = append([]*Snapshot{}, v.versions[drop:]...)
+	}
+}
+
+// Appends a folder, copying only the last chunk
+func appendChunked(chunks [][]Folder, folder Folder) [][]Folder {
+	n := len(chunks)
+	if n > 0 && len(chunks[n-1]) < snapshotChunk {
+		last := chunks[n-1]
+		return append(chunks[:n-1:n-1], append(last[:len(last):len(last)], folder))
+	}
+	return append(chunks[:n:n], []Folder{folder})
+}
+
+// Applies a mutation to the folders of its organisation, sharing every chunk it does not touch
+// Input: chunks of the organisation's folders, mutation
+// Output: new chunks
+func applyChunked(chunks [][]Folder, m Mutation) [][]Folder {
+	if m.Op == OpCreate {
+		return appendChunked(chunks, Folder{Name: m.Name, OrgId: m.OrgId, Paths: m.To})
+	}
+
+	res := make([][]Folder, 0, len(chunks))
+	for _, chunk := range chunks {
+		touched := false
+		for _, folder := range chunk {
+			if inSubtree(folder.Paths, m.From) {
+				touched = true
+				break
+			}
+		}
+		if !touched {
+			res = append(res, chunk)
+			continue
+		}
+
+		changed := make([]Folder, 0, len(chunk))
+		for _, folder := range chunk {
+			if !inSubtree(folder.Paths, m.From) {
+				changed = append(changed, folder)
+				continue
+			}
+			if m.Op == OpDelete {
+				continue
+			}
+			if folder.Paths == m.From {
+				folder.Name = m.Name
+			}
+			folder.Paths = m.To + strings.TrimPrefix(folder.Paths, m.From)
+			changed = append(changed, folder)
+		}
+		if len(changed) > 0 {
+			res = append(res, changed)
+		}
+	}
+	return res
+}
+
+// Latest returns the snapshot of the current folders.
+func (v *Versions) Latest() *Snapshot {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.versions[len(v.versions)-1]
+}
+
+// Finds the snapshot of a version
+// Input: version
+// Output: snapshot, error
+// Errors: Version no longer kept, version not made yet
+func (v *Versions) At(version uint64) (*Snapshot, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	oldest := v.versions[0].Version
+	if version < oldest {
+		return nil, errors.New("version is no longer kept")
+	} else if version-oldest >= uint64(len(v.versions)) {
+		return nil, errors.New("version does not exist")
+	}
+	return v.versions[version-oldest], nil
+}
+
+// Finds the snapshot of the folders as they were at a time
+// Input: time
+// Output: snapshot of the last version made at or before the time, error
+// Errors: Time before the oldest version kept
+func (v *Versions) AsOf(t time.Time) (*Snapshot, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	for i := len(v.versions) - 1; i >= 0; i-- {
+		if !v.versions[i].Time.After(t) {
+			return v.versions[i], nil
+		}
+	}
+	return nil, errors.New("version is no longer kept")
+}
+
+// Returns the folders of an organisation, copied so callers cannot change the snapshot
+func (s *Snapshot) folders(orgID uuid.UUID) []Folder {
+	res := []Folder{}
+	for _, chunk := range s.orgs[orgID] {
+		res = append(res, chunk...)
+	}
+	return res
+}
+
+// GetFoldersByOrgID returns all folders that belonged to an organisation as of the snapshot.
+func (s *Snapshot) GetFoldersByOrgID(orgID uuid.UUID) []Folder {
+	return s.folders(orgID)
+}
+
+// GetAllChildFolders returns the child folders of a folder as of the snapshot.
+func (s *Snapshot) GetAllChildFolders(orgID uuid.UUID, name string) ([]Folder, error) {
+	return newDriver(s.folders(orgID), nil).GetAllChildFolders(orgID, name)
+}
+
+// GetChildFoldersToDepth returns the child folders up to depth levels below a folder as of the snapshot.
+func (s *Snapshot) GetChildFoldersToDepth(orgID uuid.UUID, name string, depth int) ([]Folder, error) {
+	return newDriver(s.folders(orgID), nil).GetChildFoldersToDepth(orgID, name, depth)
+}
+
+// GetAncestorFolders returns the ancestors of a folder as of the snapshot, starting from the root.
+func (s *Snapshot) GetAncestorFolders(orgID uuid.UUID, name string) ([]Folder, error) {
+	return newDriver(s.folders(orgID), nil).GetAncestorFolders(orgID, name)
+}
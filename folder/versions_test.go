@@ -0,0 +1,142 @@
+package folder_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_Versions(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	otherOrgID := uuid.Must(uuid.NewV4())
+	example1 := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+		{Name: "kilo", Paths: "kilo", OrgId: otherOrgID},
+	}
+
+	for _, d := range testDrivers {
+		t.Run(d.name, func(t *testing.T) {
+			f := d.new(t, example1)
+			versions, err := folder.NewVersions(f, folder.VersionLimit{})
+			assert.NoError(t, err)
+
+			_, err = f.MoveFolder("bravo", "golf")
+			assert.NoError(t, err)
+			tuesday := time.Now()
+			_, err = f.RenameFolder("charlie", "delta")
+			assert.NoError(t, err)
+			_, err = f.DeleteFolder("golf")
+			assert.NoError(t, err)
+
+			latest := versions.Latest()
+			assert.Equal(t, uint64(3), latest.Version)
+			assert.Equal(t, f.GetFoldersByOrgID(defaultOrgID), latest.GetFoldersByOrgID(defaultOrgID))
+
+			first, err := versions.At(0)
+			assert.NoError(t, err)
+			assert.Equal(t, example1[:4], first.GetFoldersByOrgID(defaultOrgID))
+			assert.Equal(t, example1[4:], first.GetFoldersByOrgID(otherOrgID))
+
+			// Where was charlie on Tuesday?
+			snapshot, err := versions.AsOf(tuesday)
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(1), snapshot.Version)
+			ancestors, err := snapshot.GetAncestorFolders(defaultOrgID, "charlie")
+			assert.NoError(t, err)
+			assert.Equal(t, []folder.Folder{
+				{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+				{Name: "bravo", Paths: "golf.bravo", OrgId: defaultOrgID},
+			}, ancestors)
+			children, err := snapshot.GetAllChildFolders(defaultOrgID, "golf")
+			assert.NoError(t, err)
+			assert.Len(t, children, 2)
+			_, err = latest.GetAllChildFolders(defaultOrgID, "golf")
+			assert.Error(t, err)
+
+			_, err = versions.At(4)
+			assert.EqualError(t, err, "version does not exist")
+			_, err = versions.AsOf(tuesday.Add(-time.Hour))
+			assert.EqualError(t, err, "version is no longer kept")
+		})
+	}
+}
+
+func Test_folder_Versions_Sharing(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	sample := folder.GetSampleData()
+	f := folder.NewDriver(append([]folder.Folder{}, sample...))
+	versions, err := folder.NewVersions(f, folder.VersionLimit{})
+	assert.NoError(t, err)
+
+	// Moves spread the subtree of a root folder across the whole folder set
+	roots := []string{}
+	for _, folder := range f.GetFoldersByOrgID(defaultOrgID) {
+		if folder.Paths == folder.Name {
+			roots = append(roots, folder.Name)
+		}
+	}
+	want := [][]folder.Folder{f.GetFoldersByOrgID(defaultOrgID)}
+	for i := 1; i < len(roots) && i < 10; i++ {
+		_, err := f.MoveFolder(roots[i], roots[i-1])
+		assert.NoError(t, err)
+		want = append(want, f.GetFoldersByOrgID(defaultOrgID))
+	}
+	_, err = f.DeleteFolder(roots[0])
+	assert.NoError(t, err)
+	want = append(want, f.GetFoldersByOrgID(defaultOrgID))
+
+	for i := range want {
+		snapshot, err := versions.At(uint64(i))
+		assert.NoError(t, err)
+		assert.Equal(t, want[i], snapshot.GetFoldersByOrgID(defaultOrgID))
+	}
+}
+
+func Test_folder_Versions_Limit(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+
+	tests := [...]struct {
+		testName string
+		limit    folder.VersionLimit
+		oldest   uint64
+	}{
+		{testName: "unbounded", limit: folder.VersionLimit{}, oldest: 0},
+		{testName: "versions", limit: folder.VersionLimit{Versions: 2}, oldest: 3},
+		{testName: "age", limit: folder.VersionLimit{Age: time.Hour}, oldest: 0},
+		// The version replaced by the latest change was current until just now
+		{testName: "short age", limit: folder.VersionLimit{Age: time.Nanosecond}, oldest: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			f := folder.NewDriver([]folder.Folder{})
+			versions, err := folder.NewVersions(f, tt.limit)
+			assert.NoError(t, err)
+
+			for _, name := range []string{"alpha", "bravo", "charlie", "delta"} {
+				_, err := f.CreateFolder(defaultOrgID, name, "")
+				assert.NoError(t, err)
+				time.Sleep(time.Millisecond)
+			}
+
+			_, err = versions.At(tt.oldest)
+			assert.NoError(t, err)
+			if tt.oldest > 0 {
+				_, err = versions.At(tt.oldest - 1)
+				assert.EqualError(t, err, "version is no longer kept")
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package folder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// Nested tree documents only hold names, so every folder needs its parent in the set
+// and a name that matches the last label of its path
+func checkNestedTrees(trees []*orgTree) error {
+	var check func(nodes []*treeNode) error
+	check = func(nodes []*treeNode) error {
+		for _, node := range nodes {
+			if node.Name != pathLabel(node.Paths) {
+				return fmt.Errorf("folder name %q does not match its path %q", node.Name, node.Paths)
+			}
+			if err := check(node.Children); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, tree := range trees {
+		for _, root := range tree.Roots {
+			if strings.Contains(root.Paths, ".") {
+				return fmt.Errorf("folder %q has no parent in organisation %s", root.Paths, tree.OrgId)
+			}
+		}
+		if err := check(tree.Roots); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Encodes folders as a nested YAML document of organisation → folder → children, leaves have no value:
+//
+//	c1556e17-b7c0-45a3-a6ae-9546248fb17a:
+//	    alpha:
+//	        bravo:
+//	            charlie:
+//	        delta:
+//	    echo:
+//
+// Input: folders
+// Output: YAML document, error
+// Errors: Duplicate folders, folders missing their parent, names not matching their path
+func MarshalYAMLTree(folders []Folder) ([]byte, error) {
+	trees, err := buildTrees(folders)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNestedTrees(trees); err != nil {
+		return nil, err
+	}
+
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+	for _, tree := range trees {
+		doc.Content = append(doc.Content, yamlScalar(tree.OrgId.String()), yamlFolders(tree.Roots))
+	}
+	return yaml.Marshal(doc)
+}
+
+func yamlScalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func yamlFolders(nodes []*treeNode) *yaml.Node {
+	if len(nodes) == 0 {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}
+	}
+
+	mapping := &yaml.Node{Kind: yaml.MappingNode}
+	for _, node := range nodes {
+		mapping.Content = append(mapping.Content, yamlScalar(node.Name), yamlFolders(node.Children))
+	}
+	return mapping
+}
+
+// Decodes a nested YAML document written by MarshalYAMLTree into flat folders, parents before children
+// Input: YAML document
+// Output: slice of folders, error
+// Errors: Invalid YAML, invalid organisation IDs, invalid folder names, reported with their line number
+func UnmarshalYAMLTree(b []byte) ([]Folder, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	folders := []Folder{}
+	if len(doc.Content) == 0 {
+		return folders, nil
+	}
+	root := doc.Content[0]
+	if isYAMLNull(root) {
+		return folders, nil
+	} else if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: expected a mapping of organisation IDs", root.Line)
+	}
+
+	for i := 0; i < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		orgID, err := uuid.FromString(key.Value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid organisation ID %q", key.Line, key.Value)
+		}
+
+		folders, err = appendYAMLFolders(folders, orgID, "", value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return folders, nil
+}
+
+func isYAMLNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+func appendYAMLFolders(folders []Folder, orgID uuid.UUID, parent string, node *yaml.Node) ([]Folder, error) {
+	if isYAMLNull(node) {
+		return folders, nil
+	} else if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: expected a mapping of folder names", node.Line)
+	}
+
+	seen := map[string]bool{}
+	for i := 0; i < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if err := checkFolderName(key.Value); err != nil {
+			return nil, fmt.Errorf("line %d: %w", key.Line, err)
+		} else if seen[key.Value] {
+			return nil, fmt.Errorf("line %d: duplicate folder name %q", key.Line, key.Value)
+		}
+		seen[key.Value] = true
+
+		paths := key.Value
+		if parent != "" {
+			paths = parent + "." + key.Value
+		}
+		folders = append(folders, Folder{Name: key.Value, OrgId: orgID, Paths: paths})
+
+		var err error
+		folders, err = appendYAMLFolders(folders, orgID, paths, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return folders, nil
+}
@@ -0,0 +1,99 @@
+package folder_test
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_YAMLTree(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	secondaryOrgID := uuid.FromStringOrNil("38b9879b-f73b-4b0e-b9d9-4fc4c23643a7")
+
+	// Same folders as the move_folder_test.go examples
+	fixture := `c1556e17-b7c0-45a3-a6ae-9546248fb17a:
+    alpha:
+        bravo:
+            charlie:
+        delta:
+            echo:
+    golf:
+38b9879b-f73b-4b0e-b9d9-4fc4c23643a7:
+    foxtrot:
+`
+	folders := []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+		{Name: "delta", Paths: "alpha.delta", OrgId: defaultOrgID},
+		{Name: "echo", Paths: "alpha.delta.echo", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+		{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+	}
+
+	get, err := folder.UnmarshalYAMLTree([]byte(fixture))
+	assert.NoError(t, err)
+	assert.Equal(t, folders, get)
+
+	b, err := folder.MarshalYAMLTree(folders)
+	assert.NoError(t, err)
+	assert.Equal(t, fixture, string(b))
+}
+
+func Test_folder_YAMLTree_Error(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+
+	t.Run("Marshal folder without parent", func(t *testing.T) {
+		_, err := folder.MarshalYAMLTree([]folder.Folder{
+			{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		})
+		assert.ErrorContains(t, err, `folder "alpha.bravo" has no parent`)
+	})
+
+	t.Run("Marshal name not matching path", func(t *testing.T) {
+		_, err := folder.MarshalYAMLTree([]folder.Folder{
+			{Name: "bravo", Paths: "alpha", OrgId: defaultOrgID},
+		})
+		assert.ErrorContains(t, err, `folder name "bravo" does not match its path "alpha"`)
+	})
+
+	tests := [...]struct {
+		testName string
+		input    string
+		want     string
+	}{
+		{
+			testName: "Invalid organisation ID",
+			input:    "not-an-org:\n    alpha:\n",
+			want:     `line 1: invalid organisation ID "not-an-org"`,
+		},
+		{
+			testName: "Folder name with a dot",
+			input:    folder.DefaultOrgID + ":\n    alpha:\n        bra.vo:\n",
+			want:     `line 3: invalid folder name "bra.vo": contains '.'`,
+		},
+		{
+			testName: "List instead of mapping",
+			input:    folder.DefaultOrgID + ":\n    - alpha\n",
+			want:     "line 2: expected a mapping of folder names",
+		},
+		{
+			testName: "Duplicate sibling",
+			input:    folder.DefaultOrgID + ":\n    alpha:\n    alpha:\n",
+			want:     "line 3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			_, err := folder.UnmarshalYAMLTree([]byte(tt.input))
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
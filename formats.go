@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+)
+
+// Formats a data file can be stored in, picked from the file extension
+var dataFormats = map[string]string{
+	".json":   "json",
+	".ndjson": "ndjson",
+	".jsonl":  "ndjson",
+	".csv":    "csv",
+	".yaml":   "yaml",
+	".yml":    "yaml",
+	".toml":   "toml",
+	".sql":    "ltree-copy",
+	".copy":   "ltree-copy",
+}
+
+// Works out the format of a file from its extension, defaulting to JSON
+func formatFromPath(path string) string {
+	if format, ok := dataFormats[strings.ToLower(filepath.Ext(path))]; ok {
+		return format
+	}
+	return "json"
+}
+
+// Decodes folders in any format the CLI can read
+// Input: reader, format name, how ltree labels are read
+// Output: slice of folders, error
+// Errors: Unknown format, decoding errors
+func readFolders(r io.Reader, format string, ltree folder.LtreeImportOptions) ([]folder.Folder, error) {
+	switch format {
+	case "json":
+		return folder.LoadFolders(r)
+	case "ndjson":
+		folders := []folder.Folder{}
+		for f, err := range folder.ReadFoldersNDJSON(r) {
+			if err != nil {
+				return nil, err
+			}
+			folders = append(folders, f)
+		}
+		return folders, nil
+	case "csv":
+		return folder.ReadFoldersCSV(r)
+	case "yaml", "toml":
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		if format == "yaml" {
+			return folder.UnmarshalYAMLTree(b)
+		}
+		return folder.UnmarshalTOMLTree(b)
+	case "ltree-copy":
+		return folder.ImportLtreeCopy(r, ltree)
+	case "ltree-csv":
+		return folder.ImportLtreeCSV(r, ltree)
+	default:
+		return nil, usageErrorf("cannot read format %q", format)
+	}
+}
+
+// Encodes folders in any format the CLI can write
+// Input: writer, folders, format name
+// Output: error
+// Errors: Unknown format, encoding errors, IO errors
+func writeFolders(w io.Writer, folders []folder.Folder, format string) error {
+	switch format {
+	case "json":
+		b, err := json.MarshalIndent(folders, "", "\t")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	case "ndjson":
+		return folder.WriteFoldersNDJSON(w, slices.Values(folders))
+	case "csv":
+		return folder.WriteFoldersCSV(w, folders)
+	case "yaml", "toml":
+		marshal := folder.MarshalYAMLTree
+		if format == "toml" {
+			marshal = folder.MarshalTOMLTree
+		}
+		b, err := marshal(folders)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	case "ltree-copy", "sql":
+		opts := folder.LtreeExportOptions{Format: folder.LtreeCopy}
+		if format == "sql" {
+			opts.Format = folder.LtreeInsert
+		}
+		return folder.ExportLtreeSQL(w, folders, opts)
+	case "text":
+		return folder.RenderTree(w, folders, folder.TreeOptions{})
+	case "dot":
+		return folder.ExportDOT(w, folders, folder.GraphOptions{})
+	case "mermaid":
+		return folder.ExportMermaid(w, folders, folder.GraphOptions{})
+	case "html":
+		return folder.RenderHTML(w, folders, folder.HTMLOptions{})
+	default:
+		return usageErrorf("cannot write format %q", format)
+	}
+}
+
+// Encodes folders in the format of a data file and replaces it atomically
+func saveFolders(path string, folders []folder.Folder) error {
+	var b bytes.Buffer
+	if err := writeFolders(&b, folders, formatFromPath(path)); err != nil {
+		return err
+	}
+	return folder.WriteFileAtomic(path, b.Bytes())
+}
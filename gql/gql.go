@@ -0,0 +1,141 @@
+// Package gql serves the folder hierarchy over GraphQL, see schema.graphql.
+// A breadcrumb and one level of children can be fetched in a single request:
+//
+//	{ folder(org: "…", name: "bravo") { ancestors { name } children { name } } }
+package gql
+
+import (
+	_ "embed"
+	"errors"
+	"net/http"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+//go:embed schema.graphql
+var Schema string
+
+// Creates an HTTP handler accepting GraphQL queries as JSON POST bodies
+// Input: driver
+// Output: handler, error
+// Errors: Schema not matching the resolvers
+func NewHandler(driver folder.IDriver) (http.Handler, error) {
+	schema, err := graphql.ParseSchema(Schema, &resolver{driver: driver})
+	if err != nil {
+		return nil, err
+	}
+	return &relay.Handler{Schema: schema}, nil
+}
+
+type resolver struct {
+	driver folder.IDriver
+}
+
+type folderResolver struct {
+	r *resolver
+	f folder.Folder
+}
+
+func (r *resolver) wrap(folders []folder.Folder) []*folderResolver {
+	res := make([]*folderResolver, 0, len(folders))
+	for _, f := range folders {
+		res = append(res, &folderResolver{r: r, f: f})
+	}
+	return res
+}
+
+func parseOrgID(id graphql.ID) (uuid.UUID, error) {
+	orgID, err := uuid.FromString(string(id))
+	if err != nil {
+		return uuid.Nil, errors.New("invalid organisation ID")
+	}
+	return orgID, nil
+}
+
+func (r *resolver) Folders(args struct{ Org graphql.ID }) ([]*folderResolver, error) {
+	orgID, err := parseOrgID(args.Org)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.wrap(r.driver.GetFoldersByOrgID(orgID)), nil
+}
+
+func (r *resolver) Folder(args struct {
+	Org  graphql.ID
+	Name string
+}) (*folderResolver, error) {
+	orgID, err := parseOrgID(args.Org)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range r.driver.GetFoldersByOrgID(orgID) {
+		if f.Name == args.Name {
+			return &folderResolver{r: r, f: f}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *resolver) MoveFolder(args struct {
+	Name string
+	Dst  string
+}) (*folderResolver, error) {
+	folders, err := r.driver.MoveFolder(args.Name, args.Dst)
+	if err != nil {
+		return nil, err
+	}
+
+	// MoveFolder picks the last folder with the name, so does this
+	for i := len(folders) - 1; i >= 0; i-- {
+		if folders[i].Name == args.Name {
+			return &folderResolver{r: r, f: folders[i]}, nil
+		}
+	}
+	return nil, errors.New("moved folder not found")
+}
+
+func (f *folderResolver) Name() string {
+	return f.f.Name
+}
+
+func (f *folderResolver) OrgId() graphql.ID {
+	return graphql.ID(f.f.OrgId.String())
+}
+
+func (f *folderResolver) Paths() string {
+	return f.f.Paths
+}
+
+func (f *folderResolver) Parent() (*folderResolver, error) {
+	ancestors, err := f.Ancestors()
+	if err != nil || len(ancestors) == 0 {
+		return nil, err
+	}
+	return ancestors[len(ancestors)-1], nil
+}
+
+func (f *folderResolver) Ancestors() ([]*folderResolver, error) {
+	folders, err := f.r.driver.GetAncestorFolders(f.f.OrgId, f.f.Name)
+	if err != nil {
+		return nil, err
+	}
+	return f.r.wrap(folders), nil
+}
+
+func (f *folderResolver) Descendants() ([]*folderResolver, error) {
+	return f.Children(struct{ Depth int32 }{})
+}
+
+// Folders up to depth levels below, every level when depth is not positive
+func (f *folderResolver) Children(args struct{ Depth int32 }) ([]*folderResolver, error) {
+	folders, err := f.r.driver.GetChildFoldersToDepth(f.f.OrgId, f.f.Name, int(args.Depth))
+	if err != nil {
+		return nil, err
+	}
+	return f.r.wrap(folders), nil
+}
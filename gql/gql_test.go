@@ -0,0 +1,94 @@
+package gql_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/gql"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_gql(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	secondaryOrgID := uuid.FromStringOrNil("38b9879b-f73b-4b0e-b9d9-4fc4c23643a7")
+	org := `"` + folder.DefaultOrgID + `"`
+
+	tests := [...]struct {
+		testName string
+		query    string
+		want     string
+	}{
+		{
+			testName: "Breadcrumb and one level of children",
+			query:    `{ folder(org: ` + org + `, name: "bravo") { paths parent { name } ancestors { name } children { name } } }`,
+			want:     `{"data":{"folder":{"paths":"alpha.bravo","parent":{"name":"alpha"},"ancestors":[{"name":"alpha"}],"children":[{"name":"charlie"}]}}}`,
+		},
+		{
+			testName: "Children to a depth",
+			query:    `{ folder(org: ` + org + `, name: "alpha") { children(depth: 2) { paths } descendants { name } } }`,
+			want:     `{"data":{"folder":{"children":[{"paths":"alpha.bravo"},{"paths":"alpha.bravo.charlie"},{"paths":"alpha.delta"}],"descendants":[{"name":"bravo"},{"name":"charlie"},{"name":"echo"},{"name":"delta"}]}}}`,
+		},
+		{
+			testName: "Root folder has no parent",
+			query:    `{ folder(org: ` + org + `, name: "alpha") { parent { name } } }`,
+			want:     `{"data":{"folder":{"parent":null}}}`,
+		},
+		{
+			testName: "Missing folder",
+			query:    `{ folder(org: ` + org + `, name: "zulu") { name } }`,
+			want:     `{"data":{"folder":null}}`,
+		},
+		{
+			testName: "Folders by org",
+			query:    `{ folders(org: "` + secondaryOrgID.String() + `") { name orgId } }`,
+			want:     `{"data":{"folders":[{"name":"foxtrot","orgId":"` + secondaryOrgID.String() + `"}]}}`,
+		},
+		{
+			testName: "Move",
+			query:    `mutation { moveFolder(name: "bravo", dst: "golf") { paths parent { name } descendants { paths } } }`,
+			want:     `{"data":{"moveFolder":{"paths":"golf.bravo","parent":{"name":"golf"},"descendants":[{"paths":"golf.bravo.charlie"},{"paths":"golf.bravo.charlie.echo"}]}}}`,
+		},
+		{
+			testName: "Move error",
+			query:    `mutation { moveFolder(name: "alpha", dst: "charlie") { paths } }`,
+			want:     `{"errors":[{"message":"cannot move a folder to a child of itself","path":["moveFolder"]}],"data":null}`,
+		},
+		{
+			testName: "Invalid org",
+			query:    `{ folders(org: "not-an-org") { name } }`,
+			want:     `{"errors":[{"message":"invalid organisation ID","path":["folders"]}],"data":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			handler, err := gql.NewHandler(folder.NewDriver([]folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+				{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+				{Name: "echo", Paths: "alpha.bravo.charlie.echo", OrgId: defaultOrgID},
+				{Name: "delta", Paths: "alpha.delta", OrgId: defaultOrgID},
+				{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+				{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+			}))
+			assert.NoError(t, err)
+
+			body, err := json.Marshal(map[string]string{"query": tt.query})
+			assert.NoError(t, err)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body))))
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, tt.want, rec.Body.String())
+		})
+	}
+}
@@ -1,22 +1,596 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
 
 	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/gql"
+	"github.com/georgechieng-sc/interns-2022/server"
 	"github.com/gofrs/uuid"
 )
 
+// Exit codes, usage errors are kept apart so scripts can tell a typo from a failed operation
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+func usageErrorf(format string, args ...any) error {
+	return usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// Returned by validate after the problems have been printed
+var errInvalid = errors.New("folders are invalid")
+
+// Flags and streams shared by every subcommand
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	data   string
+	org    string
+	format string
+	orgID  uuid.UUID
+	// options for ltree dumps read with import, diff and patch, --data files are always unescaped
+	ltree folder.LtreeImportOptions
+
+	// subcommand specific flags
+	dryRun bool
+	from   string
+	depth  int
+	ascii  bool
+	color  bool
+	addr   string
+	check  bool
+}
+
+type command struct {
+	name    string
+	args    string
+	summary string
+	// number of positional arguments, optional arguments are allowed up to max
+	min, max int
+	// registers flags beyond --data, --org, --format and --ltree-escaped
+	flags func(fs *flag.FlagSet, c *cli)
+	run   func(c *cli, args []string) error
+}
+
+func dryRunFlag(fs *flag.FlagSet, c *cli) {
+	fs.BoolVar(&c.dryRun, "dry-run", false, "print the result instead of saving it to --data")
+}
+
+var commands = []command{
+	{name: "list", summary: "list folders, optionally only those in --org", run: runList},
+	{name: "children", args: "NAME", summary: "list every folder below NAME in --org", min: 1, max: 1, run: runChildren},
+	{name: "ancestors", args: "NAME", summary: "list the folders above NAME in --org, from the root", min: 1, max: 1, run: runAncestors},
+	{name: "move", args: "NAME DST", summary: "move NAME and its children into DST", min: 2, max: 2, flags: dryRunFlag, run: runMove},
+	{name: "rename", args: "NAME NEW_NAME", summary: "rename NAME, updating the paths of its children", min: 2, max: 2, flags: dryRunFlag, run: runRename},
+	{name: "delete", args: "NAME", summary: "delete NAME and its children", min: 1, max: 1, flags: dryRunFlag, run: runDelete},
+	{
+		name: "tree", args: "[NAME]", summary: "draw folders as a tree (--format text, dot, mermaid or html)", max: 1, run: runTree,
+		flags: func(fs *flag.FlagSet, c *cli) {
+			fs.IntVar(&c.depth, "depth", 0, "maximum depth to draw, 0 draws everything")
+			fs.BoolVar(&c.ascii, "ascii", false, "draw branches with plain ASCII")
+			fs.BoolVar(&c.color, "color", false, "colour output with ANSI escapes")
+		},
+	},
+	{name: "validate", summary: "check folders are well formed (--format text or json)", run: runValidate},
+	{name: "generate", summary: "generate random folders, saving them to --data if given", run: runGenerate},
+	{
+		name: "import", args: "FILE", summary: "read FILE (- for stdin), saving it to --data if given, where --org replaces only that organisation", min: 1, max: 1, run: runImport,
+		flags: func(fs *flag.FlagSet, c *cli) {
+			fs.StringVar(&c.from, "from", "", "format of FILE, defaults to its extension (json, ndjson, csv, yaml, toml, ltree-copy, ltree-csv)")
+		},
+	},
+	{
+		name: "diff", args: "FILE", summary: "list the changes from --data to FILE (- for stdin), --format text or json", min: 1, max: 1, run: runDiff,
+		flags: func(fs *flag.FlagSet, c *cli) {
+			fs.StringVar(&c.from, "from", "", "format of FILE, defaults to its extension (json, ndjson, csv, yaml, toml, ltree-copy, ltree-csv)")
+		},
+	},
+	{
+		name: "patch", args: "FILE", summary: "apply the JSON patch FILE (- for stdin) to --data, all or nothing", min: 1, max: 1, run: runPatch,
+		flags: func(fs *flag.FlagSet, c *cli) {
+			dryRunFlag(fs, c)
+			fs.BoolVar(&c.check, "check", false, "only check the patch applies, listing the changes it makes")
+		},
+	},
+	{name: "export", summary: "write folders in --format", run: runExport},
+	{name: "shell", summary: "browse and edit folders interactively, starting in --org", run: runShell},
+	{
+		name: "serve", summary: "serve the folder API and GraphQL over HTTP, saving changes to a JSON --data file", run: runServe,
+		flags: func(fs *flag.FlagSet, c *cli) {
+			fs.StringVar(&c.addr, "addr", "localhost:8080", "address to listen on")
+		},
+	},
+}
+
 func main() {
-	orgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// Runs a subcommand and returns the process exit code
+// Input: command line arguments without the program name, standard streams
+// Output: exit code
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stderr)
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == args[0] {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+		printUsage(stderr)
+		return exitUsage
+	}
+
+	c := &cli{stdin: stdin, stdout: stdout, stderr: stderr}
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: %s %s [flags] %s\n\n%s\n\nflags:\n", programName, cmd.name, cmd.args, cmd.summary)
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&c.data, "data", "", "data file (json, ndjson, csv, yaml or toml by extension), defaults to the bundled sample data")
+	fs.StringVar(&c.org, "org", "", "organisation ID")
+	fs.StringVar(&c.format, "format", "", "output format (json, ndjson, csv, yaml, toml, sql, ltree-copy, text, dot, mermaid, html)")
+	fs.BoolVar(&c.ltree.Unescape, "ltree-escaped", false, "decode ltree labels escaped by this tool in files read with import or diff, --data files are always decoded")
+	if cmd.flags != nil {
+		cmd.flags(fs, c)
+	}
+
+	if err := fs.Parse(args[1:]); errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	} else if err != nil {
+		return exitUsage
+	}
+	if fs.NArg() < cmd.min || fs.NArg() > cmd.max {
+		fs.Usage()
+		return exitUsage
+	}
+	if c.org != "" {
+		orgID, err := uuid.FromString(c.org)
+		if err != nil {
+			fmt.Fprintf(stderr, "invalid --org %q: %v\n", c.org, err)
+			return exitUsage
+		}
+		c.orgID = orgID
+	}
+
+	err := cmd.run(c, fs.Args())
+	var usage usageError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &usage):
+		fmt.Fprintf(stderr, "%s: %v\n", cmd.name, err)
+		return exitUsage
+	case errors.Is(err, errInvalid):
+		return exitError
+	default:
+		fmt.Fprintf(stderr, "%s: %v\n", cmd.name, err)
+		return exitError
+	}
+}
+
+const programName = "folders"
+
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "usage: %s <command> [flags] [args]\n\ncommands:\n", programName)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-28s %s\n", strings.TrimSpace(cmd.name+" "+cmd.args), cmd.summary)
+	}
+	fmt.Fprintf(w, "\nrun '%s <command> -h' for the flags of a command\n", programName)
+}
+
+// Returns the output format, falling back to the command's default
+func (c *cli) outputFormat(fallback string) string {
+	if c.format == "" {
+		return fallback
+	}
+	return c.format
+}
+
+// Loads the folders from --data, or the bundled sample data when it is not set
+func (c *cli) load() ([]folder.Folder, error) {
+	if c.data == "" {
+		return folder.GetSampleData(), nil
+	}
+
+	file, err := os.Open(c.data)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// saveFolders escapes ltree labels, so a data file is read back the same way
+	folders, err := readFolders(file, formatFromPath(c.data), folder.LtreeImportOptions{Unescape: true})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", c.data, err)
+	}
+	return folders, nil
+}
+
+// Keeps only the folders in --org when it is set
+func (c *cli) filter(folders []folder.Folder) []folder.Folder {
+	if c.org == "" {
+		return folders
+	}
+	return folder.NewDriver(folders).GetFoldersByOrgID(c.orgID)
+}
+
+func (c *cli) requireOrg() error {
+	if c.org == "" {
+		return usageErrorf("--org is required")
+	}
+	return nil
+}
+
+func (c *cli) write(folders []folder.Folder, fallback string) error {
+	return writeFolders(c.stdout, folders, c.outputFormat(fallback))
+}
+
+func runList(c *cli, args []string) error {
+	folders, err := c.load()
+	if err != nil {
+		return err
+	}
+	return c.write(c.filter(folders), "json")
+}
+
+func runChildren(c *cli, args []string) error {
+	return c.query(args[0], folder.IDriver.GetAllChildFolders)
+}
+
+func runAncestors(c *cli, args []string) error {
+	return c.query(args[0], folder.IDriver.GetAncestorFolders)
+}
 
-	res := folder.GetAllFolders()
+func (c *cli) query(name string, fn func(folder.IDriver, uuid.UUID, string) ([]folder.Folder, error)) error {
+	if err := c.requireOrg(); err != nil {
+		return err
+	}
+	folders, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	res, err := fn(folder.NewDriver(folders), c.orgID, name)
+	if err != nil {
+		return err
+	}
+	return c.write(res, "json")
+}
+
+func runMove(c *cli, args []string) error {
+	return c.mutate(args[0], func(d folder.IDriver, orgID uuid.UUID) ([]folder.Folder, error) {
+		return d.MoveFolderInOrg(orgID, args[0], args[1])
+	})
+}
+
+func runRename(c *cli, args []string) error {
+	return c.mutate(args[0], func(d folder.IDriver, orgID uuid.UUID) ([]folder.Folder, error) {
+		return d.RenameFolderInOrg(orgID, args[0], args[1])
+	})
+}
+
+func runDelete(c *cli, args []string) error {
+	return c.mutate(args[0], func(d folder.IDriver, orgID uuid.UUID) ([]folder.Folder, error) {
+		return d.DeleteFolderInOrg(orgID, args[0])
+	})
+}
+
+// Applies a change and saves the result back to --data, or prints it when there is no data file or on --dry-run
+// The change is made in --org, or in the one organisation using the name when --org is not set,
+// so folders with the same name elsewhere are left alone.
+func (c *cli) mutate(name string, change func(d folder.IDriver, orgID uuid.UUID) ([]folder.Folder, error)) error {
+	folders, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	orgID := c.orgID
+	if c.org == "" {
+		orgs := []uuid.UUID{}
+		for _, f := range folders {
+			if f.Name == name && !slices.Contains(orgs, f.OrgId) {
+				orgs = append(orgs, f.OrgId)
+			}
+		}
+		if len(orgs) > 1 {
+			return usageErrorf("folder %q is in %d organisations, pick one with --org", name, len(orgs))
+		} else if len(orgs) == 0 {
+			return folder.ErrFolderNotFound
+		}
+		orgID = orgs[0]
+	}
+
+	res, err := change(folder.NewDriver(folders), orgID)
+	if err != nil {
+		return err
+	}
+
+	if c.data == "" || c.dryRun {
+		return c.write(c.filter(res), "json")
+	}
+	return saveFolders(c.data, res)
+}
+
+func runTree(c *cli, args []string) error {
+	folders, err := c.load()
+	if err != nil {
+		return err
+	}
+	folders = c.filter(folders)
+
+	opts := folder.GraphOptions{OrgID: c.orgID}
+	if len(args) == 1 {
+		if err := c.requireOrg(); err != nil {
+			return err
+		}
+		opts.Root = args[0]
+
+		driver := folder.NewDriver(folders)
+		children, err := driver.GetAllChildFolders(c.orgID, args[0])
+		if err != nil {
+			return err
+		}
+		for _, f := range folders {
+			if f.Name == args[0] {
+				folders = append([]folder.Folder{f}, children...)
+				break
+			}
+		}
+	}
+
+	switch format := c.outputFormat("text"); format {
+	case "text":
+		return folder.RenderTree(c.stdout, folders, folder.TreeOptions{MaxDepth: c.depth, ASCII: c.ascii, Color: c.color})
+	case "dot":
+		return folder.ExportDOT(c.stdout, folders, opts)
+	case "mermaid":
+		return folder.ExportMermaid(c.stdout, folders, opts)
+	default:
+		return writeFolders(c.stdout, folders, format)
+	}
+}
+
+func runValidate(c *cli, args []string) error {
+	folders, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	problems := []string{}
+	for _, problem := range folder.ValidateFolders(c.filter(folders)) {
+		problems = append(problems, problem.Error())
+	}
+
+	switch c.outputFormat("text") {
+	case "text":
+		for _, problem := range problems {
+			fmt.Fprintln(c.stdout, problem)
+		}
+	case "json":
+		fmt.Fprintf(c.stdout, "%s\n", folder.MarshalJson(problems))
+	default:
+		return usageErrorf("validate only supports --format text or json")
+	}
+
+	if len(problems) > 0 {
+		fmt.Fprintf(c.stderr, "%d problems found\n", len(problems))
+		return errInvalid
+	}
+	return nil
+}
+
+func runGenerate(c *cli, args []string) error {
+	folders := folder.GenerateData()
+	if c.data != "" {
+		return saveFolders(c.data, folders)
+	}
+	return c.write(folders, "json")
+}
+
+func runImport(c *cli, args []string) error {
+	folders, err := c.readFile(args[0])
+	if err != nil {
+		return err
+	}
+	folders = c.filter(folders)
+
+	if c.data == "" {
+		return c.write(folders, "json")
+	}
+	if c.org != "" {
+		// Only the folders of --org are replaced, the other organisations in the data file are kept
+		existing, err := c.load()
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		others := slices.DeleteFunc(existing, func(f folder.Folder) bool { return f.OrgId == c.orgID })
+		folders = append(others, folders...)
+	}
+	return saveFolders(c.data, folders)
+}
+
+// Reads folders from a file named on the command line, or stdin for -, in the --from format
+func (c *cli) readFile(path string) ([]folder.Folder, error) {
+	format := c.from
+	if format == "" {
+		format = formatFromPath(path)
+	}
+
+	var r io.Reader = c.stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	folders, err := readFolders(r, format, c.ltree)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return folders, nil
+}
+
+func runDiff(c *cli, args []string) error {
+	before, err := c.load()
+	if err != nil {
+		return err
+	}
+	after, err := c.readFile(args[0])
+	if err != nil {
+		return err
+	}
+	changes := folder.Diff(c.filter(before), c.filter(after))
+
+	switch c.outputFormat("text") {
+	case "text":
+		for _, change := range changes {
+			fmt.Fprintln(c.stdout, describeChange(change))
+		}
+	case "json":
+		fmt.Fprintf(c.stdout, "%s\n", folder.MarshalJson(changes))
+	default:
+		return usageErrorf("diff only supports --format text or json")
+	}
+	return nil
+}
+
+// Describes a change on one line, e.g. "moved alpha.bravo -> golf.bravo (and 2 below)"
+func describeChange(change folder.Change) string {
+	var line string
+	switch change.Type {
+	case folder.ChangeAdded:
+		line = fmt.Sprintf("added %s", change.To)
+	case folder.ChangeRemoved:
+		line = fmt.Sprintf("removed %s", change.From)
+	case folder.ChangeMovedOrg:
+		line = fmt.Sprintf("%s %s -> %s in %s", change.Type, change.From, change.To, change.OrgId)
+	default:
+		line = fmt.Sprintf("%s %s -> %s", change.Type, change.From, change.To)
+	}
+	if change.Descendants > 0 {
+		line += fmt.Sprintf(" (and %d below)", change.Descendants)
+	}
+	return line
+}
+
+func runPatch(c *cli, args []string) error {
+	folders, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = c.stdin
+	if args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+	patch, err := folder.ReadPatch(r)
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	driver := folder.NewDriver(folders)
+	if c.check {
+		res, err := folder.CheckPatch(driver, patch)
+		if err != nil {
+			return err
+		}
+		for _, change := range folder.Diff(folders, res) {
+			fmt.Fprintln(c.stdout, describeChange(change))
+		}
+		return nil
+	}
+
+	res, err := folder.ApplyPatch(driver, patch)
+	if err != nil {
+		return err
+	}
+	if c.data == "" || c.dryRun {
+		return c.write(c.filter(res), "json")
+	}
+	return saveFolders(c.data, res)
+}
+
+func runExport(c *cli, args []string) error {
+	folders, err := c.load()
+	if err != nil {
+		return err
+	}
+	return c.write(c.filter(folders), "json")
+}
+
+func runServe(c *cli, args []string) error {
+	var driver folder.IDriver
+	switch {
+	case c.data == "":
+		driver = folder.NewDriver(folder.GetSampleData())
+	case formatFromPath(c.data) == "json":
+		var err error
+		if driver, err = folder.NewDriverWithStore(folder.NewFileStore(c.data)); err != nil {
+			return err
+		}
+	default:
+		return usageErrorf("serve can only save changes to a JSON --data file")
+	}
+
+	handler, err := serveHandler(driver)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stderr, "listening on %s\n", c.addr)
+	return http.ListenAndServe(c.addr, handler)
+}
 
-	// example usage
-	folderDriver := folder.NewDriver(res)
-	orgFolder := folderDriver.GetFoldersByOrgID(orgID)
+// Serves the HTTP API, with GraphQL at /graphql, over one driver
+// Input: driver
+// Output: handler, error
+// Errors: Schema not matching the resolvers
+func serveHandler(driver folder.IDriver) (http.Handler, error) {
+	graphql, err := gql.NewHandler(driver)
+	if err != nil {
+		return nil, err
+	}
 
-	folder.PrettyPrint(res)
-	fmt.Printf("\n Folders for orgID: %s", orgID)
-	folder.PrettyPrint(orgFolder)
+	mux := http.NewServeMux()
+	mux.Handle("/", server.New(driver))
+	mux.Handle("/graphql", graphql)
+	return mux, nil
 }
@@ -0,0 +1,380 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const testFolders = `[
+	{"name": "alpha", "paths": "alpha", "org_id": "c1556e17-b7c0-45a3-a6ae-9546248fb17a"},
+	{"name": "bravo", "paths": "alpha.bravo", "org_id": "c1556e17-b7c0-45a3-a6ae-9546248fb17a"},
+	{"name": "charlie", "paths": "alpha.bravo.charlie", "org_id": "c1556e17-b7c0-45a3-a6ae-9546248fb17a"},
+	{"name": "golf", "paths": "golf", "org_id": "c1556e17-b7c0-45a3-a6ae-9546248fb17a"}
+]`
+
+func writeTestData(t *testing.T, name string, data string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func runCLI(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func Test_run(t *testing.T) {
+	t.Parallel()
+
+	tests := [...]struct {
+		testName string
+		args     []string
+		stdin    string
+		code     int
+		stdout   string
+		stderr   string
+	}{
+		{
+			testName: "No command",
+			code:     exitUsage,
+			stderr:   "usage: folders <command>",
+		},
+		{
+			testName: "Unknown command",
+			args:     []string{"frobnicate"},
+			code:     exitUsage,
+			stderr:   `unknown command "frobnicate"`,
+		},
+		{
+			testName: "Missing argument",
+			args:     []string{"children", "--org", folder.DefaultOrgID},
+			code:     exitUsage,
+			stderr:   "usage: folders children [flags] NAME",
+		},
+		{
+			testName: "Invalid org",
+			args:     []string{"list", "--org", "not-an-org"},
+			code:     exitUsage,
+			stderr:   `invalid --org "not-an-org"`,
+		},
+		{
+			testName: "Children without org",
+			args:     []string{"children", "alpha"},
+			code:     exitUsage,
+			stderr:   "children: --org is required",
+		},
+		{
+			testName: "Unknown format",
+			args:     []string{"export", "--format", "xml"},
+			code:     exitUsage,
+			stderr:   `export: cannot write format "xml"`,
+		},
+		{
+			testName: "Children",
+			args:     []string{"children", "--org", folder.DefaultOrgID, "--format", "csv", "alpha"},
+			code:     exitOK,
+			stdout:   "alpha.bravo.charlie",
+		},
+		{
+			testName: "Ancestors of a missing folder",
+			args:     []string{"ancestors", "--org", folder.DefaultOrgID, "zulu"},
+			code:     exitError,
+			stderr:   "ancestors: folder does not exist",
+		},
+		{
+			testName: "Move dry run prints the result",
+			args:     []string{"move", "--dry-run", "--format", "csv", "bravo", "golf"},
+			code:     exitOK,
+			stdout:   "golf.bravo.charlie",
+		},
+		{
+			testName: "Tree of a subtree",
+			args:     []string{"tree", "--org", folder.DefaultOrgID, "--ascii", "bravo"},
+			code:     exitOK,
+			stdout:   "`-- alpha.bravo\n    `-- charlie\n",
+		},
+		{
+			testName: "Import from stdin",
+			args:     []string{"import", "--from", "ndjson", "--format", "csv", "-"},
+			stdin:    `{"name": "alpha", "paths": "alpha", "org_id": "c1556e17-b7c0-45a3-a6ae-9546248fb17a"}` + "\n",
+			code:     exitOK,
+			stdout:   "alpha,c1556e17-b7c0-45a3-a6ae-9546248fb17a,alpha",
+		},
+		{
+			testName: "Import plain ltree from stdin",
+			args:     []string{"import", "--from", "ltree-csv", "--format", "csv", "-"},
+			stdin:    "path,org_id\ntop.my_docs,c1556e17-b7c0-45a3-a6ae-9546248fb17a\n",
+			code:     exitOK,
+			stdout:   "my_docs,c1556e17-b7c0-45a3-a6ae-9546248fb17a,top.my_docs",
+		},
+		{
+			testName: "Import escaped ltree from stdin",
+			args:     []string{"import", "--from", "ltree-csv", "--ltree-escaped", "--format", "csv", "-"},
+			stdin:    "path,org_id\ntop.my__docs,c1556e17-b7c0-45a3-a6ae-9546248fb17a\n",
+			code:     exitOK,
+			stdout:   "my_docs,c1556e17-b7c0-45a3-a6ae-9546248fb17a,top.my_docs",
+		},
+		{
+			testName: "Diff against stdin",
+			args:     []string{"diff", "--from", "ndjson", "-"},
+			stdin: `{"name": "alpha", "paths": "alpha", "org_id": "c1556e17-b7c0-45a3-a6ae-9546248fb17a"}
+{"name": "bravo", "paths": "golf.bravo", "org_id": "c1556e17-b7c0-45a3-a6ae-9546248fb17a"}
+{"name": "charlie", "paths": "golf.bravo.charlie", "org_id": "c1556e17-b7c0-45a3-a6ae-9546248fb17a"}
+{"name": "golf", "paths": "golf", "org_id": "c1556e17-b7c0-45a3-a6ae-9546248fb17a"}
+`,
+			code:   exitOK,
+			stdout: "moved alpha.bravo -> golf.bravo (and 1 below)\n",
+		},
+		{
+			testName: "Check a patch",
+			args:     []string{"patch", "--check", "-"},
+			stdin:    `[{"op": "move", "org_id": "` + folder.DefaultOrgID + `", "name": "bravo", "dst": "golf"}, {"op": "rename", "org_id": "` + folder.DefaultOrgID + `", "name": "alpha", "new_name": "able"}]`,
+			code:     exitOK,
+			stdout:   "renamed alpha -> able\nmoved alpha.bravo -> golf.bravo (and 1 below)\n",
+		},
+		{
+			testName: "Patch that does not apply",
+			args:     []string{"patch", "-"},
+			stdin:    `[{"op": "delete", "org_id": "` + folder.DefaultOrgID + `", "name": "alpha"}, {"op": "move", "org_id": "` + folder.DefaultOrgID + `", "name": "bravo", "dst": "golf"}]`,
+			code:     exitError,
+			stderr:   "patch: operation 1 (move bravo): source folder does not exist in the specified organisation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			args := tt.args
+			if len(args) > 0 && args[0] != "frobnicate" && args[0] != "import" {
+				args = append(args[:1:1], append([]string{"--data", writeTestData(t, "folders.json", testFolders)}, args[1:]...)...)
+			}
+
+			code, stdout, stderr := runCLI(tt.stdin, args...)
+			assert.Equal(t, tt.code, code, stderr)
+			assert.Contains(t, stdout, tt.stdout)
+			assert.Contains(t, stderr, tt.stderr)
+		})
+	}
+}
+
+func Test_run_Mutations(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	path := writeTestData(t, "folders.yaml", "")
+	code, _, stderr := runCLI("", "import", "--data", path, writeTestData(t, "folders.json", testFolders))
+	assert.Equal(t, exitOK, code, stderr)
+
+	code, _, stderr = runCLI("", "move", "--data", path, "--org", folder.DefaultOrgID, "bravo", "golf")
+	assert.Equal(t, exitOK, code, stderr)
+	code, _, stderr = runCLI("", "rename", "--data", path, "charlie", "delta")
+	assert.Equal(t, exitOK, code, stderr)
+	code, _, stderr = runCLI("", "delete", "--data", path, "alpha")
+	assert.Equal(t, exitOK, code, stderr)
+
+	// The data file keeps its YAML format across saves
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	get, err := folder.UnmarshalYAMLTree(b)
+	assert.NoError(t, err)
+	assert.Equal(t, []folder.Folder{
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "golf.bravo", OrgId: defaultOrgID},
+		{Name: "delta", Paths: "golf.bravo.delta", OrgId: defaultOrgID},
+	}, get)
+
+	code, _, stderr = runCLI("", "validate", "--data", path)
+	assert.Equal(t, exitOK, code, stderr)
+
+	code, _, stderr = runCLI(`[{"op": "create", "org_id": "`+folder.DefaultOrgID+`", "name": "hotel", "parent": "delta"}]`,
+		"patch", "--data", path, "-")
+	assert.Equal(t, exitOK, code, stderr)
+	code, stdout, stderr := runCLI("", "children", "--data", path, "--org", folder.DefaultOrgID, "--format", "csv", "delta")
+	assert.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, stdout, "golf.bravo.delta.hotel")
+}
+
+func Test_run_Mutations_OtherOrganisation(t *testing.T) {
+	t.Parallel()
+
+	otherOrgID := "38b9879b-f73b-4b0e-b9d9-4fc4c23643a7"
+	data := `[
+	{"name": "alpha", "paths": "alpha", "org_id": "` + otherOrgID + `"},
+	{"name": "golf", "paths": "golf", "org_id": "` + otherOrgID + `"},
+	{"name": "alpha", "paths": "alpha", "org_id": "c1556e17-b7c0-45a3-a6ae-9546248fb17a"},
+	{"name": "echo", "paths": "echo", "org_id": "c1556e17-b7c0-45a3-a6ae-9546248fb17a"}
+]`
+
+	tests := [...]struct {
+		testName string
+		args     []string
+		code     int
+		stdout   string
+		stderr   string
+	}{
+		{
+			testName: "Name in two organisations",
+			args:     []string{"delete", "alpha"},
+			code:     exitUsage,
+			stderr:   `delete: folder "alpha" is in 2 organisations, pick one with --org`,
+		},
+		{
+			testName: "Move in the organisation",
+			args:     []string{"move", "--org", folder.DefaultOrgID, "--format", "csv", "alpha", "echo"},
+			code:     exitOK,
+			stdout:   "alpha,c1556e17-b7c0-45a3-a6ae-9546248fb17a,echo.alpha",
+		},
+		{
+			testName: "Destination in another organisation",
+			args:     []string{"move", "--org", folder.DefaultOrgID, "alpha", "golf"},
+			code:     exitError,
+			stderr:   "move: destination folder does not exist in the specified organisation",
+		},
+		{
+			testName: "Rename in the organisation",
+			args:     []string{"rename", "--org", otherOrgID, "--format", "csv", "alpha", "delta"},
+			code:     exitOK,
+			stdout:   "delta," + otherOrgID + ",delta",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			args := append([]string{tt.args[0], "--dry-run", "--data", writeTestData(t, "folders.json", data)}, tt.args[1:]...)
+			code, stdout, stderr := runCLI("", args...)
+			assert.Equal(t, tt.code, code, stderr)
+			assert.Contains(t, stdout, tt.stdout)
+			assert.Contains(t, stderr, tt.stderr)
+		})
+	}
+
+	// Only the folder in --org is touched, every other folder is saved as it was
+	path := writeTestData(t, "folders.json", data)
+	code, _, stderr := runCLI("", "delete", "--data", path, "--org", otherOrgID, "alpha")
+	assert.Equal(t, exitOK, code, stderr)
+	get, err := folder.LoadFoldersFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []folder.Folder{
+		{Name: "golf", Paths: "golf", OrgId: uuid.FromStringOrNil(otherOrgID)},
+		{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil(folder.DefaultOrgID)},
+		{Name: "echo", Paths: "echo", OrgId: uuid.FromStringOrNil(folder.DefaultOrgID)},
+	}, get)
+
+	code, stdout, stderr := runCLI("", "delete", "--dry-run", "--format", "csv", "--data", writeTestData(t, "folders.json", data), "alpha")
+	assert.Equal(t, exitUsage, code, stderr)
+	assert.Empty(t, stdout)
+}
+
+func Test_run_Import_Organisation(t *testing.T) {
+	t.Parallel()
+
+	otherOrgID := "38b9879b-f73b-4b0e-b9d9-4fc4c23643a7"
+	path := writeTestData(t, "folders.json", `[
+	{"name": "alpha", "paths": "alpha", "org_id": "`+otherOrgID+`"},
+	{"name": "alpha", "paths": "alpha", "org_id": "c1556e17-b7c0-45a3-a6ae-9546248fb17a"}
+]`)
+
+	// Importing one organisation replaces its folders and keeps the rest of the data file
+	code, _, stderr := runCLI("", "import", "--data", path, "--org", folder.DefaultOrgID, writeTestData(t, "import.json", testFolders))
+	assert.Equal(t, exitOK, code, stderr)
+
+	get, err := folder.LoadFoldersFile(path)
+	assert.NoError(t, err)
+	want, err := folder.LoadFolders(strings.NewReader(testFolders))
+	assert.NoError(t, err)
+	assert.Equal(t, append([]folder.Folder{{Name: "alpha", Paths: "alpha", OrgId: uuid.FromStringOrNil(otherOrgID)}}, want...), get)
+
+	// A data file that does not exist yet is created
+	path = filepath.Join(t.TempDir(), "new.json")
+	code, _, stderr = runCLI("", "import", "--data", path, "--org", folder.DefaultOrgID, writeTestData(t, "import.json", testFolders))
+	assert.Equal(t, exitOK, code, stderr)
+	get, err = folder.LoadFoldersFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, want, get)
+}
+
+func Test_run_Mutations_Ltree(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	path := filepath.Join(t.TempDir(), "folders.copy")
+	code, _, stderr := runCLI("", "import", "--data", path, writeTestData(t, "folders.csv", `name,org_id,paths
+top,c1556e17-b7c0-45a3-a6ae-9546248fb17a,top
+my_docs,c1556e17-b7c0-45a3-a6ae-9546248fb17a,top.my_docs
+golf,c1556e17-b7c0-45a3-a6ae-9546248fb17a,golf
+`))
+	assert.Equal(t, exitOK, code, stderr)
+
+	// Each save reads back the labels it escaped, so names survive repeated changes
+	code, _, stderr = runCLI("", "move", "--data", path, "top", "golf")
+	assert.Equal(t, exitOK, code, stderr)
+	code, _, stderr = runCLI("", "rename", "--data", path, "golf", "hotel")
+	assert.Equal(t, exitOK, code, stderr)
+	code, _, stderr = runCLI("", "validate", "--data", path)
+	assert.Equal(t, exitOK, code, stderr)
+
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+	get, err := folder.ImportLtreeCopy(file, folder.LtreeImportOptions{Unescape: true})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []folder.Folder{
+		{Name: "hotel", Paths: "hotel", OrgId: defaultOrgID},
+		{Name: "top", Paths: "hotel.top", OrgId: defaultOrgID},
+		{Name: "my_docs", Paths: "hotel.top.my_docs", OrgId: defaultOrgID},
+	}, get)
+}
+
+func Test_run_Validate(t *testing.T) {
+	t.Parallel()
+
+	path := writeTestData(t, "folders.json", `[
+	{"name": "bravo", "paths": "alpha.bravo", "org_id": "c1556e17-b7c0-45a3-a6ae-9546248fb17a"}
+]`)
+
+	code, stdout, stderr := runCLI("", "validate", "--data", path)
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stdout, `folder 0 "alpha.bravo"`)
+	assert.Contains(t, stderr, "1 problems found")
+}
+
+func Test_serveHandler(t *testing.T) {
+	t.Parallel()
+
+	folders, err := folder.LoadFolders(strings.NewReader(testFolders))
+	assert.NoError(t, err)
+	handler, err := serveHandler(folder.NewDriver(folders))
+	assert.NoError(t, err)
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+
+	// A change over the HTTP API is seen by GraphQL, both share the driver
+	res, err := ts.Client().Post(ts.URL+"/orgs/"+folder.DefaultOrgID+"/folders/charlie/move", "application/json", strings.NewReader(`{"dst": "golf"}`))
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	query := `{"query": "{ folder(org: \"` + folder.DefaultOrgID + `\", name: \"golf\") { children { paths } } }"}`
+	res, err = ts.Client().Post(ts.URL+"/graphql", "application/json", strings.NewReader(query))
+	assert.NoError(t, err)
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"data": {"folder": {"children": [{"paths": "golf.charlie"}]}}}`, string(body))
+}
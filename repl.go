@@ -0,0 +1,567 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chzyer/readline"
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+)
+
+// Changes the shell keeps for undo, older changes are forgotten
+const shellHistoryLimit = 100
+
+// Interactive session over a folder set, one organisation at a time.
+// The current folder is tracked by name, so moving it or one of its ancestors keeps you inside it.
+type shell struct {
+	out     io.Writer
+	data    string
+	folders []folder.Folder
+	history *folder.History
+	// reads every folder back from the driver behind the history
+	load  func() ([]folder.Folder, error)
+	org   uuid.UUID
+	cwd   string
+	dirty bool
+}
+
+type shellCommand struct {
+	args    string
+	summary string
+	run     func(s *shell, args []string) error
+}
+
+var shellCommands map[string]shellCommand
+
+func init() {
+	// Assigned in init as help refers back to the table
+	shellCommands = map[string]shellCommand{
+		"pwd":   {summary: "print the current folder", run: (*shell).pwd},
+		"cd":    {args: "[PATH]", summary: "change folder, / or no argument goes to the root", run: (*shell).cd},
+		"ls":    {args: "[PATH]", summary: "list the folders inside PATH", run: (*shell).ls},
+		"tree":  {args: "[PATH] [DEPTH]", summary: "draw PATH and everything below it", run: (*shell).tree},
+		"mkdir": {args: "NAME", summary: "create a folder inside the current folder", run: (*shell).mkdir},
+		"mv":    {args: "PATH DST", summary: "move PATH and its children into DST", run: (*shell).mv},
+		"rm":    {args: "PATH", summary: "delete PATH and its children", run: (*shell).rm},
+		"org":   {args: "[ID]", summary: "list organisations or switch to one", run: (*shell).switchOrg},
+		"undo":  {summary: "undo the last change", run: (*shell).undoChange},
+		"redo":  {summary: "redo the last undone change", run: (*shell).redoChange},
+		"save":  {summary: "save changes to --data", run: (*shell).save},
+		"help":  {summary: "list commands", run: (*shell).help},
+	}
+}
+
+func runShell(c *cli, args []string) error {
+	folders, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	s := &shell{out: c.stdout, data: c.data, org: c.orgID}
+	if err := s.reset(folders); err != nil {
+		return err
+	}
+	if c.org == "" {
+		// Start in the default organisation, or the first one when the data has none of it
+		s.org = uuid.FromStringOrNil(folder.DefaultOrgID)
+		if orgs := s.orgs(); len(orgs) > 0 && !slices.Contains(orgs, s.org) {
+			s.org = orgs[0]
+		}
+	}
+
+	var readLine func() (string, error)
+	if file, ok := c.stdin.(*os.File); ok && readline.IsTerminal(int(file.Fd())) {
+		rl, err := readline.NewEx(&readline.Config{
+			Prompt:       s.prompt(),
+			AutoComplete: s,
+			Stdin:        file,
+			Stdout:       c.stdout,
+			Stderr:       c.stderr,
+		})
+		if err != nil {
+			return err
+		}
+		defer rl.Close()
+		readLine = func() (string, error) {
+			rl.SetPrompt(s.prompt())
+			return rl.Readline()
+		}
+	} else {
+		// Scripted input, no prompt or line editing
+		scanner := bufio.NewScanner(c.stdin)
+		readLine = func() (string, error) {
+			if !scanner.Scan() {
+				if err := scanner.Err(); err != nil {
+					return "", err
+				}
+				return "", io.EOF
+			}
+			return scanner.Text(), nil
+		}
+	}
+
+	for {
+		line, err := readLine()
+		if errors.Is(err, readline.ErrInterrupt) {
+			continue
+		} else if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "exit" || fields[0] == "quit" {
+			break
+		}
+
+		cmd, ok := shellCommands[fields[0]]
+		if !ok {
+			fmt.Fprintf(c.stderr, "unknown command %q, try help\n", fields[0])
+			continue
+		}
+		if err := cmd.run(s, fields[1:]); err != nil {
+			fmt.Fprintf(c.stderr, "%s: %v\n", fields[0], err)
+		}
+	}
+
+	if s.dirty {
+		fmt.Fprintln(c.stderr, "unsaved changes discarded")
+	}
+	return nil
+}
+
+// Replaces the folder set, starting a new history of changes
+func (s *shell) reset(folders []folder.Folder) error {
+	driver := folder.NewDriver(slices.Clone(folders))
+	history, err := folder.NewHistory(driver, folder.HistoryLimit{Changes: shellHistoryLimit})
+	if err != nil {
+		return err
+	}
+
+	s.folders = folders
+	s.history = history
+	s.load = driver.(interface {
+		Load() ([]folder.Folder, error)
+	}).Load
+	return nil
+}
+
+func (s *shell) prompt() string {
+	return s.org.String()[:8] + ":" + s.path(s.cwd) + "> "
+}
+
+// Returns every organisation in the folder set, sorted
+func (s *shell) orgs() []uuid.UUID {
+	orgs := []uuid.UUID{}
+	for _, f := range s.folders {
+		if !slices.Contains(orgs, f.OrgId) {
+			orgs = append(orgs, f.OrgId)
+		}
+	}
+	sort.Slice(orgs, func(i, j int) bool { return orgs[i].String() < orgs[j].String() })
+	return orgs
+}
+
+// Finds a folder by name in the current organisation, the last one like the driver when a name is used twice
+func (s *shell) find(name string) (folder.Folder, bool) {
+	res, found := folder.Folder{}, false
+	for _, f := range s.folders {
+		if f.OrgId == s.org && f.Name == name {
+			res, found = f, true
+		}
+	}
+	return res, found
+}
+
+// Returns the direct children of a folder, or the root folders for an empty name
+func (s *shell) children(name string) []folder.Folder {
+	parent := ""
+	if name != "" {
+		f, _ := s.find(name)
+		parent = f.Paths + "."
+	}
+
+	res := []folder.Folder{}
+	for _, f := range s.folders {
+		if f.OrgId == s.org && strings.HasPrefix(f.Paths, parent) && !strings.Contains(f.Paths[len(parent):], ".") {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// Formats a folder as a slash separated path
+func (s *shell) path(name string) string {
+	f, ok := s.find(name)
+	if !ok {
+		return "/"
+	}
+	return "/" + strings.ReplaceAll(f.Paths, ".", "/")
+}
+
+// Resolves a path argument to a folder name, "" being the root.
+// Paths can be absolute (/alpha/bravo), relative (bravo/.., ltree style alpha.bravo),
+// or just the name of any folder in the organisation.
+func (s *shell) resolve(arg string) (string, error) {
+	name := s.cwd
+	if strings.HasPrefix(arg, "/") {
+		name = ""
+	}
+
+	resolved := true
+	for _, segment := range strings.Split(arg, "/") {
+		labels := []string{segment}
+		if segment != "." && segment != ".." {
+			labels = strings.Split(segment, ".")
+		}
+		for _, label := range labels {
+			switch label {
+			case "", ".":
+			case "..":
+				if f, ok := s.find(name); ok {
+					name = ""
+					if i := strings.LastIndex(f.Paths, "."); i >= 0 {
+						name = s.nameAt(f.Paths[:i])
+					}
+				}
+			default:
+				child, ok := s.child(name, label)
+				if !ok {
+					resolved = false
+				}
+				name = child
+			}
+			if !resolved {
+				break
+			}
+		}
+		if !resolved {
+			break
+		}
+	}
+	if resolved {
+		return name, nil
+	}
+
+	if _, ok := s.find(arg); ok {
+		return arg, nil
+	}
+	return "", fmt.Errorf("%s: no such folder", arg)
+}
+
+func (s *shell) child(name string, label string) (string, bool) {
+	for _, f := range s.children(name) {
+		if f.Name == label {
+			return f.Name, true
+		}
+	}
+	return "", false
+}
+
+func (s *shell) nameAt(paths string) string {
+	for _, f := range s.folders {
+		if f.OrgId == s.org && f.Paths == paths {
+			return f.Name
+		}
+	}
+	return ""
+}
+
+// Runs a change through the history so it can be undone
+func (s *shell) change(fn func(folder.IDriver) ([]folder.Folder, error)) error {
+	res, err := fn(s.history)
+	if err != nil {
+		return err
+	}
+	s.changed(slices.Clone(res))
+	return nil
+}
+
+// Takes the folders after a change, leaving the current folder if it is gone
+func (s *shell) changed(folders []folder.Folder) {
+	s.folders = folders
+	s.dirty = true
+	if _, ok := s.find(s.cwd); !ok {
+		s.cwd = ""
+	}
+}
+
+func (s *shell) pwd(args []string) error {
+	fmt.Fprintln(s.out, s.path(s.cwd))
+	return nil
+}
+
+func (s *shell) cd(args []string) error {
+	if len(args) > 1 {
+		return errors.New("usage: cd [PATH]")
+	}
+	if len(args) == 0 {
+		s.cwd = ""
+		return nil
+	}
+
+	name, err := s.resolve(args[0])
+	if err != nil {
+		return err
+	}
+	s.cwd = name
+	return nil
+}
+
+func (s *shell) ls(args []string) error {
+	name := s.cwd
+	if len(args) > 1 {
+		return errors.New("usage: ls [PATH]")
+	} else if len(args) == 1 {
+		var err error
+		if name, err = s.resolve(args[0]); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range s.children(name) {
+		if len(s.children(f.Name)) > 0 {
+			fmt.Fprintln(s.out, f.Name+"/")
+		} else {
+			fmt.Fprintln(s.out, f.Name)
+		}
+	}
+	return nil
+}
+
+func (s *shell) tree(args []string) error {
+	name := s.cwd
+	opts := folder.TreeOptions{}
+	if len(args) > 2 {
+		return errors.New("usage: tree [PATH] [DEPTH]")
+	}
+	for _, arg := range args {
+		if depth, err := strconv.Atoi(arg); err == nil {
+			opts.MaxDepth = depth
+			continue
+		}
+		var err error
+		if name, err = s.resolve(arg); err != nil {
+			return err
+		}
+	}
+
+	folders := s.history.GetFoldersByOrgID(s.org)
+	if name != "" {
+		f, _ := s.find(name)
+		children, err := s.history.GetAllChildFolders(s.org, name)
+		if err != nil {
+			return err
+		}
+		folders = append([]folder.Folder{f}, children...)
+	}
+	return folder.RenderTree(s.out, folders, opts)
+}
+
+func (s *shell) mkdir(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: mkdir NAME")
+	}
+	return s.change(func(d folder.IDriver) ([]folder.Folder, error) {
+		return d.CreateFolder(s.org, args[0], s.cwd)
+	})
+}
+
+func (s *shell) mv(args []string) error {
+	if len(args) != 2 {
+		return errors.New("usage: mv PATH DST")
+	}
+	name, err := s.resolve(args[0])
+	if err != nil {
+		return err
+	}
+	dst, err := s.resolve(args[1])
+	if err != nil {
+		return err
+	}
+	if name == "" || dst == "" {
+		return errors.New("cannot move to or from the root")
+	}
+
+	return s.change(func(d folder.IDriver) ([]folder.Folder, error) {
+		return d.MoveFolderInOrg(s.org, name, dst)
+	})
+}
+
+func (s *shell) rm(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: rm PATH")
+	}
+	name, err := s.resolve(args[0])
+	if err != nil {
+		return err
+	} else if name == "" {
+		return errors.New("cannot remove the root")
+	}
+
+	return s.change(func(d folder.IDriver) ([]folder.Folder, error) {
+		return d.DeleteFolderInOrg(s.org, name)
+	})
+}
+
+func (s *shell) switchOrg(args []string) error {
+	if len(args) == 0 {
+		for _, org := range s.orgs() {
+			marker := "  "
+			if org == s.org {
+				marker = "* "
+			}
+			fmt.Fprintln(s.out, marker+org.String())
+		}
+		return nil
+	}
+
+	// A unique prefix is enough, IDs are long to type
+	matches := []uuid.UUID{}
+	for _, org := range s.orgs() {
+		if strings.HasPrefix(org.String(), args[0]) {
+			matches = append(matches, org)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		org, err := uuid.FromString(args[0])
+		if err != nil {
+			return fmt.Errorf("%s: no such organisation", args[0])
+		}
+		// An empty organisation, mkdir can fill it
+		s.org = org
+	case 1:
+		s.org = matches[0]
+	default:
+		return fmt.Errorf("%s: matches %d organisations", args[0], len(matches))
+	}
+	s.cwd = ""
+	return nil
+}
+
+func (s *shell) undoChange(args []string) error {
+	return s.replay(s.history.Undo)
+}
+
+func (s *shell) redoChange(args []string) error {
+	return s.replay(s.history.Redo)
+}
+
+// Undoes or redoes a change and reads back the folders it left
+func (s *shell) replay(fn func() error) error {
+	if err := fn(); err != nil {
+		return err
+	}
+	folders, err := s.load()
+	if err != nil {
+		return err
+	}
+	s.changed(folders)
+	return nil
+}
+
+func (s *shell) save(args []string) error {
+	if s.data == "" {
+		return errors.New("no --data file to save to")
+	}
+	if err := saveFolders(s.data, s.folders); err != nil {
+		return err
+	}
+	s.dirty = false
+	return nil
+}
+
+func (s *shell) help(args []string) error {
+	names := []string{}
+	for name := range shellCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		cmd := shellCommands[name]
+		fmt.Fprintf(s.out, "  %-20s %s\n", strings.TrimSpace(name+" "+cmd.args), cmd.summary)
+	}
+	fmt.Fprintf(s.out, "  %-20s %s\n", "exit", "leave the shell")
+	return nil
+}
+
+// Do implements readline.AutoCompleter.
+// The first word completes to a command, later words to folder paths or, for org, organisation IDs.
+func (s *shell) Do(line []rune, pos int) ([][]rune, int) {
+	before := string(line[:pos])
+	fields := strings.Fields(before)
+	word := ""
+	if len(fields) > 0 && !strings.HasSuffix(before, " ") {
+		word = fields[len(fields)-1]
+		fields = fields[:len(fields)-1]
+	}
+
+	candidates := []string{}
+	switch {
+	case len(fields) == 0:
+		for name := range shellCommands {
+			candidates = append(candidates, name)
+		}
+	case fields[0] == "org":
+		for _, org := range s.orgs() {
+			candidates = append(candidates, org.String())
+		}
+	default:
+		candidates = s.completePath(word)
+	}
+	sort.Strings(candidates)
+
+	res := [][]rune{}
+	for _, candidate := range slices.Compact(candidates) {
+		if strings.HasPrefix(candidate, word) {
+			res = append(res, []rune(candidate[len(word):]))
+		}
+	}
+	return res, len([]rune(word))
+}
+
+// Completes the last segment of a path against the folders in its directory.
+// A bare word also completes to any folder name in the organisation.
+func (s *shell) completePath(word string) []string {
+	dir, prefix := "", word
+	if i := strings.LastIndex(word, "/"); i >= 0 {
+		dir, prefix = word[:i+1], word[i+1:]
+	}
+
+	name := s.cwd
+	if dir != "" {
+		var err error
+		if name, err = s.resolve(dir); err != nil {
+			return nil
+		}
+	}
+
+	candidates := []string{}
+	for _, f := range s.children(name) {
+		if strings.HasPrefix(f.Name, prefix) {
+			candidates = append(candidates, dir+f.Name)
+		}
+	}
+	if dir == "" {
+		for _, f := range s.folders {
+			if f.OrgId == s.org && strings.HasPrefix(f.Name, prefix) {
+				candidates = append(candidates, f.Name)
+			}
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_shell(t *testing.T) {
+	t.Parallel()
+
+	path := writeTestData(t, "folders.json", testFolders)
+	script := `pwd
+cd alpha/bravo
+pwd
+cd ..
+ls
+mkdir india
+mv bravo golf
+ls /golf
+cd /golf/bravo/charlie
+pwd
+undo
+pwd
+ls /alpha
+redo
+rm india
+nope
+save
+`
+	code, stdout, stderr := runCLI(script, "shell", "--data", path)
+	assert.Equal(t, exitOK, code, stderr)
+	// Undo keeps you in the folder you were in, back where it came from
+	assert.Equal(t, "/\n/alpha/bravo\nbravo/\nbravo/\n/golf/bravo/charlie\n/alpha/bravo/charlie\nbravo/\nindia\n", stdout)
+	assert.Equal(t, "unknown command \"nope\", try help\n", stderr)
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+	get, err := folder.LoadFolders(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "golf.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "golf.bravo.charlie", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+	}, get)
+}
+
+func Test_shell_DuplicateName(t *testing.T) {
+	t.Parallel()
+
+	// A name used twice in an organisation refers to the last folder, the one the driver changes
+	path := writeTestData(t, "folders.json", `[
+	{"name": "alpha", "paths": "alpha", "org_id": "c1556e17-b7c0-45a3-a6ae-9546248fb17a"},
+	{"name": "bravo", "paths": "alpha.bravo", "org_id": "c1556e17-b7c0-45a3-a6ae-9546248fb17a"},
+	{"name": "golf", "paths": "golf", "org_id": "c1556e17-b7c0-45a3-a6ae-9546248fb17a"},
+	{"name": "bravo", "paths": "golf.bravo", "org_id": "c1556e17-b7c0-45a3-a6ae-9546248fb17a"}
+]`)
+	code, stdout, stderr := runCLI("cd bravo\npwd\ncd /\nrm bravo\nls /alpha\nls /golf\nundo\nls /golf\n", "shell", "--data", path)
+	assert.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "/golf/bravo\nbravo\nbravo\n", stdout)
+	assert.Equal(t, "unsaved changes discarded\n", stderr)
+}
+
+func Test_shell_OtherOrganisation(t *testing.T) {
+	t.Parallel()
+
+	// The other organisation uses the same names and comes last, where a lookup by name alone would land
+	otherOrgID := uuid.Must(uuid.FromString("38b9879b-f73b-4b0e-b9d9-4fc4c23643a7"))
+	data := strings.TrimSuffix(testFolders, "\n]") + `,
+	{"name": "alpha", "paths": "alpha", "org_id": "38b9879b-f73b-4b0e-b9d9-4fc4c23643a7"},
+	{"name": "bravo", "paths": "alpha.bravo", "org_id": "38b9879b-f73b-4b0e-b9d9-4fc4c23643a7"},
+	{"name": "golf", "paths": "golf", "org_id": "38b9879b-f73b-4b0e-b9d9-4fc4c23643a7"}
+]`
+	path := writeTestData(t, "folders.json", data)
+	code, _, stderr := runCLI("mv bravo golf\nrm alpha\nsave\n", "shell", "--data", path, "--org", folder.DefaultOrgID)
+	assert.Equal(t, exitOK, code, stderr)
+	assert.Empty(t, stderr)
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	get, err := folder.LoadFoldersFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []folder.Folder{
+		{Name: "bravo", Paths: "golf.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "golf.bravo.charlie", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+		{Name: "alpha", Paths: "alpha", OrgId: otherOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: otherOrgID},
+		{Name: "golf", Paths: "golf", OrgId: otherOrgID},
+	}, get)
+}
+
+func Test_shell_Complete(t *testing.T) {
+	t.Parallel()
+
+	folders, err := readFolders(strings.NewReader(testFolders), "json", folder.LtreeImportOptions{})
+	assert.NoError(t, err)
+	s := &shell{org: uuid.FromStringOrNil(folder.DefaultOrgID)}
+	s.reset(folders)
+
+	tests := [...]struct {
+		testName string
+		line     string
+		want     []string
+		length   int
+	}{
+		{
+			testName: "Command",
+			line:     "mk",
+			want:     []string{"dir"},
+			length:   2,
+		},
+		{
+			testName: "Folder name anywhere in the organisation",
+			line:     "cd ch",
+			want:     []string{"arlie"},
+			length:   2,
+		},
+		{
+			testName: "Path label",
+			line:     "ls /alpha/",
+			want:     []string{"bravo"},
+			length:   7,
+		},
+		{
+			testName: "Organisation",
+			line:     "org c1",
+			want:     []string{"556e17-b7c0-45a3-a6ae-9546248fb17a"},
+			length:   2,
+		},
+		{
+			testName: "No match",
+			line:     "cd zulu",
+			want:     []string{},
+			length:   4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			line := []rune(tt.line)
+			get, length := s.Do(line, len(line))
+
+			suffixes := []string{}
+			for _, suffix := range get {
+				suffixes = append(suffixes, string(suffix))
+			}
+			assert.Equal(t, tt.want, suffixes)
+			assert.Equal(t, tt.length, length)
+		})
+	}
+}
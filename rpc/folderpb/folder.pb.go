@@ -0,0 +1,410 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: folderpb/folder.proto
+
+package folderpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// A folder in an ltree style hierarchy, matching the JSON shape of folder.Folder.
+type Folder struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Organisation ID as a UUID string
+	OrgId string `protobuf:"bytes,2,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`
+	// Dot separated names from the root down to the folder, e.g. "alpha.bravo"
+	Paths         string `protobuf:"bytes,3,opt,name=paths,proto3" json:"paths,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Folder) Reset() {
+	*x = Folder{}
+	mi := &file_folderpb_folder_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Folder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Folder) ProtoMessage() {}
+
+func (x *Folder) ProtoReflect() protoreflect.Message {
+	mi := &file_folderpb_folder_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Folder.ProtoReflect.Descriptor instead.
+func (*Folder) Descriptor() ([]byte, []int) {
+	return file_folderpb_folder_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Folder) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Folder) GetOrgId() string {
+	if x != nil {
+		return x.OrgId
+	}
+	return ""
+}
+
+func (x *Folder) GetPaths() string {
+	if x != nil {
+		return x.Paths
+	}
+	return ""
+}
+
+type GetFoldersByOrgIDRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrgId         string                 `protobuf:"bytes,1,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFoldersByOrgIDRequest) Reset() {
+	*x = GetFoldersByOrgIDRequest{}
+	mi := &file_folderpb_folder_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFoldersByOrgIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFoldersByOrgIDRequest) ProtoMessage() {}
+
+func (x *GetFoldersByOrgIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_folderpb_folder_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFoldersByOrgIDRequest.ProtoReflect.Descriptor instead.
+func (*GetFoldersByOrgIDRequest) Descriptor() ([]byte, []int) {
+	return file_folderpb_folder_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetFoldersByOrgIDRequest) GetOrgId() string {
+	if x != nil {
+		return x.OrgId
+	}
+	return ""
+}
+
+type GetFoldersByOrgIDResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Folders       []*Folder              `protobuf:"bytes,1,rep,name=folders,proto3" json:"folders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFoldersByOrgIDResponse) Reset() {
+	*x = GetFoldersByOrgIDResponse{}
+	mi := &file_folderpb_folder_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFoldersByOrgIDResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFoldersByOrgIDResponse) ProtoMessage() {}
+
+func (x *GetFoldersByOrgIDResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_folderpb_folder_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFoldersByOrgIDResponse.ProtoReflect.Descriptor instead.
+func (*GetFoldersByOrgIDResponse) Descriptor() ([]byte, []int) {
+	return file_folderpb_folder_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetFoldersByOrgIDResponse) GetFolders() []*Folder {
+	if x != nil {
+		return x.Folders
+	}
+	return nil
+}
+
+type GetAllChildFoldersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrgId         string                 `protobuf:"bytes,1,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAllChildFoldersRequest) Reset() {
+	*x = GetAllChildFoldersRequest{}
+	mi := &file_folderpb_folder_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAllChildFoldersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAllChildFoldersRequest) ProtoMessage() {}
+
+func (x *GetAllChildFoldersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_folderpb_folder_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAllChildFoldersRequest.ProtoReflect.Descriptor instead.
+func (*GetAllChildFoldersRequest) Descriptor() ([]byte, []int) {
+	return file_folderpb_folder_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetAllChildFoldersRequest) GetOrgId() string {
+	if x != nil {
+		return x.OrgId
+	}
+	return ""
+}
+
+func (x *GetAllChildFoldersRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type MoveFolderRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Name of the destination folder
+	Dst           string `protobuf:"bytes,2,opt,name=dst,proto3" json:"dst,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveFolderRequest) Reset() {
+	*x = MoveFolderRequest{}
+	mi := &file_folderpb_folder_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveFolderRequest) ProtoMessage() {}
+
+func (x *MoveFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_folderpb_folder_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveFolderRequest.ProtoReflect.Descriptor instead.
+func (*MoveFolderRequest) Descriptor() ([]byte, []int) {
+	return file_folderpb_folder_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MoveFolderRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MoveFolderRequest) GetDst() string {
+	if x != nil {
+		return x.Dst
+	}
+	return ""
+}
+
+type MoveFolderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Folders       []*Folder              `protobuf:"bytes,1,rep,name=folders,proto3" json:"folders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveFolderResponse) Reset() {
+	*x = MoveFolderResponse{}
+	mi := &file_folderpb_folder_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveFolderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveFolderResponse) ProtoMessage() {}
+
+func (x *MoveFolderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_folderpb_folder_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveFolderResponse.ProtoReflect.Descriptor instead.
+func (*MoveFolderResponse) Descriptor() ([]byte, []int) {
+	return file_folderpb_folder_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *MoveFolderResponse) GetFolders() []*Folder {
+	if x != nil {
+		return x.Folders
+	}
+	return nil
+}
+
+var File_folderpb_folder_proto protoreflect.FileDescriptor
+
+const file_folderpb_folder_proto_rawDesc = "" +
+	"\n" +
+	"\x15folderpb/folder.proto\x12\tfolder.v1\"I\n" +
+	"\x06Folder\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x15\n" +
+	"\x06org_id\x18\x02 \x01(\tR\x05orgId\x12\x14\n" +
+	"\x05paths\x18\x03 \x01(\tR\x05paths\"1\n" +
+	"\x18GetFoldersByOrgIDRequest\x12\x15\n" +
+	"\x06org_id\x18\x01 \x01(\tR\x05orgId\"H\n" +
+	"\x19GetFoldersByOrgIDResponse\x12+\n" +
+	"\afolders\x18\x01 \x03(\v2\x11.folder.v1.FolderR\afolders\"F\n" +
+	"\x19GetAllChildFoldersRequest\x12\x15\n" +
+	"\x06org_id\x18\x01 \x01(\tR\x05orgId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"9\n" +
+	"\x11MoveFolderRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03dst\x18\x02 \x01(\tR\x03dst\"A\n" +
+	"\x12MoveFolderResponse\x12+\n" +
+	"\afolders\x18\x01 \x03(\v2\x11.folder.v1.FolderR\afolders2\x8b\x02\n" +
+	"\rFolderService\x12^\n" +
+	"\x11GetFoldersByOrgID\x12#.folder.v1.GetFoldersByOrgIDRequest\x1a$.folder.v1.GetFoldersByOrgIDResponse\x12O\n" +
+	"\x12GetAllChildFolders\x12$.folder.v1.GetAllChildFoldersRequest\x1a\x11.folder.v1.Folder0\x01\x12I\n" +
+	"\n" +
+	"MoveFolder\x12\x1c.folder.v1.MoveFolderRequest\x1a\x1d.folder.v1.MoveFolderResponseB6Z4github.com/georgechieng-sc/interns-2022/rpc/folderpbb\x06proto3"
+
+var (
+	file_folderpb_folder_proto_rawDescOnce sync.Once
+	file_folderpb_folder_proto_rawDescData []byte
+)
+
+func file_folderpb_folder_proto_rawDescGZIP() []byte {
+	file_folderpb_folder_proto_rawDescOnce.Do(func() {
+		file_folderpb_folder_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_folderpb_folder_proto_rawDesc), len(file_folderpb_folder_proto_rawDesc)))
+	})
+	return file_folderpb_folder_proto_rawDescData
+}
+
+var file_folderpb_folder_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_folderpb_folder_proto_goTypes = []any{
+	(*Folder)(nil),                    // 0: folder.v1.Folder
+	(*GetFoldersByOrgIDRequest)(nil),  // 1: folder.v1.GetFoldersByOrgIDRequest
+	(*GetFoldersByOrgIDResponse)(nil), // 2: folder.v1.GetFoldersByOrgIDResponse
+	(*GetAllChildFoldersRequest)(nil), // 3: folder.v1.GetAllChildFoldersRequest
+	(*MoveFolderRequest)(nil),         // 4: folder.v1.MoveFolderRequest
+	(*MoveFolderResponse)(nil),        // 5: folder.v1.MoveFolderResponse
+}
+var file_folderpb_folder_proto_depIdxs = []int32{
+	0, // 0: folder.v1.GetFoldersByOrgIDResponse.folders:type_name -> folder.v1.Folder
+	0, // 1: folder.v1.MoveFolderResponse.folders:type_name -> folder.v1.Folder
+	1, // 2: folder.v1.FolderService.GetFoldersByOrgID:input_type -> folder.v1.GetFoldersByOrgIDRequest
+	3, // 3: folder.v1.FolderService.GetAllChildFolders:input_type -> folder.v1.GetAllChildFoldersRequest
+	4, // 4: folder.v1.FolderService.MoveFolder:input_type -> folder.v1.MoveFolderRequest
+	2, // 5: folder.v1.FolderService.GetFoldersByOrgID:output_type -> folder.v1.GetFoldersByOrgIDResponse
+	0, // 6: folder.v1.FolderService.GetAllChildFolders:output_type -> folder.v1.Folder
+	5, // 7: folder.v1.FolderService.MoveFolder:output_type -> folder.v1.MoveFolderResponse
+	5, // [5:8] is the sub-list for method output_type
+	2, // [2:5] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_folderpb_folder_proto_init() }
+func file_folderpb_folder_proto_init() {
+	if File_folderpb_folder_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_folderpb_folder_proto_rawDesc), len(file_folderpb_folder_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_folderpb_folder_proto_goTypes,
+		DependencyIndexes: file_folderpb_folder_proto_depIdxs,
+		MessageInfos:      file_folderpb_folder_proto_msgTypes,
+	}.Build()
+	File_folderpb_folder_proto = out.File
+	file_folderpb_folder_proto_goTypes = nil
+	file_folderpb_folder_proto_depIdxs = nil
+}
@@ -0,0 +1,101 @@
+// Package rpc serves folder operations over gRPC, see folderpb/folder.proto.
+//
+// The generated code in folderpb is rebuilt from this directory with
+//
+//	buf generate
+//
+// which needs protoc-gen-go and protoc-gen-go-grpc on the PATH.
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/rpc/folderpb"
+	"github.com/gofrs/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Service struct {
+	folderpb.UnimplementedFolderServiceServer
+
+	driver folder.IDriver
+}
+
+func NewService(driver folder.IDriver) *Service {
+	return &Service{driver: driver}
+}
+
+func (s *Service) GetFoldersByOrgID(ctx context.Context, req *folderpb.GetFoldersByOrgIDRequest) (*folderpb.GetFoldersByOrgIDResponse, error) {
+	orgID, err := parseOrgID(req.GetOrgId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &folderpb.GetFoldersByOrgIDResponse{Folders: toProto(s.driver.GetFoldersByOrgID(orgID))}, nil
+}
+
+func (s *Service) GetAllChildFolders(req *folderpb.GetAllChildFoldersRequest, stream folderpb.FolderService_GetAllChildFoldersServer) error {
+	orgID, err := parseOrgID(req.GetOrgId())
+	if err != nil {
+		return err
+	}
+
+	folders, err := s.driver.GetAllChildFolders(orgID, req.GetName())
+	if err != nil {
+		return toStatus(err)
+	}
+
+	for _, f := range folders {
+		if err := stream.Send(folderToProto(f)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) MoveFolder(ctx context.Context, req *folderpb.MoveFolderRequest) (*folderpb.MoveFolderResponse, error) {
+	folders, err := s.driver.MoveFolder(req.GetName(), req.GetDst())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &folderpb.MoveFolderResponse{Folders: toProto(folders)}, nil
+}
+
+func parseOrgID(s string) (uuid.UUID, error) {
+	orgID, err := uuid.FromString(s)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "invalid organisation ID")
+	}
+	return orgID, nil
+}
+
+func toProto(folders []folder.Folder) []*folderpb.Folder {
+	res := make([]*folderpb.Folder, 0, len(folders))
+	for _, f := range folders {
+		res = append(res, folderToProto(f))
+	}
+	return res
+}
+
+func folderToProto(f folder.Folder) *folderpb.Folder {
+	return &folderpb.Folder{Name: f.Name, OrgId: f.OrgId.String(), Paths: f.Paths}
+}
+
+// Maps driver errors to a gRPC status
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, folder.ErrFolderNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, folder.ErrFolderExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, folder.ErrInvalidMove):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, folder.ErrInvalidName):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,130 @@
+package rpc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/rpc"
+	"github.com/georgechieng-sc/interns-2022/rpc/folderpb"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestClient(t *testing.T, folders []folder.Folder) folderpb.FolderServiceClient {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	folderpb.RegisterFolderServiceServer(srv, rpc.NewService(folder.NewDriver(folders)))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return folderpb.NewFolderServiceClient(conn)
+}
+
+func Test_rpc_Service(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	secondaryOrgID := uuid.FromStringOrNil("38b9879b-f73b-4b0e-b9d9-4fc4c23643a7")
+	c := newTestClient(t, []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+		{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+	})
+	ctx := context.Background()
+
+	byOrg, err := c.GetFoldersByOrgID(ctx, &folderpb.GetFoldersByOrgIDRequest{OrgId: secondaryOrgID.String()})
+	assert.NoError(t, err)
+	assert.Len(t, byOrg.GetFolders(), 1)
+	assert.Equal(t, "foxtrot", byOrg.GetFolders()[0].GetPaths())
+
+	moved, err := c.MoveFolder(ctx, &folderpb.MoveFolderRequest{Name: "bravo", Dst: "golf"})
+	assert.NoError(t, err)
+	assert.Len(t, moved.GetFolders(), 5)
+
+	stream, err := c.GetAllChildFolders(ctx, &folderpb.GetAllChildFoldersRequest{OrgId: defaultOrgID.String(), Name: "golf"})
+	assert.NoError(t, err)
+	paths := []string{}
+	for {
+		f, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, defaultOrgID.String(), f.GetOrgId())
+		paths = append(paths, f.GetPaths())
+	}
+	assert.Equal(t, []string{"golf.bravo", "golf.bravo.charlie"}, paths)
+}
+
+func Test_rpc_Service_Error(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	c := newTestClient(t, []folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+	})
+	ctx := context.Background()
+
+	childErr := func(req *folderpb.GetAllChildFoldersRequest) error {
+		stream, err := c.GetAllChildFolders(ctx, req)
+		if err != nil {
+			return err
+		}
+		_, err = stream.Recv()
+		return err
+	}
+
+	tests := [...]struct {
+		testName string
+		err      error
+		code     codes.Code
+	}{
+		{
+			testName: "Invalid org",
+			err: func() error {
+				_, err := c.GetFoldersByOrgID(ctx, &folderpb.GetFoldersByOrgIDRequest{OrgId: "not-an-org"})
+				return err
+			}(),
+			code: codes.InvalidArgument,
+		},
+		{
+			testName: "Children of missing folder",
+			err:      childErr(&folderpb.GetAllChildFoldersRequest{OrgId: defaultOrgID.String(), Name: "zulu"}),
+			code:     codes.NotFound,
+		},
+		{
+			testName: "Move into a child",
+			err: func() error {
+				_, err := c.MoveFolder(ctx, &folderpb.MoveFolderRequest{Name: "alpha", Dst: "bravo"})
+				return err
+			}(),
+			code: codes.FailedPrecondition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			assert.Equal(t, tt.code, status.Code(tt.err), "got %v", tt.err)
+		})
+	}
+}
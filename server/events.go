@@ -0,0 +1,80 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+)
+
+// Comment lines sent while no events arrive, so proxies keep the connection open
+var heartbeatInterval = 15 * time.Second
+
+// Streams folder changes as server-sent events, e.g. for a browser EventSource.
+// Query parameters org and path narrow the stream to an organisation or subtree.
+// Each event has its sequence number as the SSE id, so a reconnecting EventSource resumes
+// after it through the Last-Event-ID header, other clients can pass since instead.
+// A "lost" event means the missed events are gone and the client should reload its folders.
+func (s *Server) streamEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: "streaming is not supported"})
+		return
+	}
+
+	filter := folder.EventFilter{Path: r.URL.Query().Get("path")}
+	if org := r.URL.Query().Get("org"); org != "" {
+		orgID, err := uuid.FromString(org)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid organisation ID"})
+			return
+		}
+		filter.OrgID = orgID
+	}
+	since := r.Header.Get("Last-Event-ID")
+	if since == "" {
+		since = r.URL.Query().Get("since")
+	}
+	if since != "" {
+		seq, err := strconv.ParseUint(since, 10, 64)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid sequence number"})
+			return
+		}
+		filter.Since = seq
+	}
+
+	events := s.driver.Subscribe(filter)
+	defer s.driver.Unsubscribe(events)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		case e, ok := <-events:
+			if !ok {
+				// Dropped for falling behind, the client reconnects and resumes from its last id
+				return
+			}
+			b, err := json.Marshal(e)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.Seq, e.Type, b)
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,104 @@
+package server_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/stretchr/testify/assert"
+)
+
+type sseEvent struct {
+	id, event string
+	data      folder.Event
+}
+
+// Opens an event stream and returns a function reading its next event
+func openStream(t *testing.T, url string, lastEventID string) func() sseEvent {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	assert.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { res.Body.Close() })
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	scanner := bufio.NewScanner(res.Body)
+	return func() sseEvent {
+		var e sseEvent
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "" && e.id != "":
+				return e
+			case strings.HasPrefix(line, "id: "):
+				e.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				e.event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e.data))
+			}
+		}
+		t.Fatalf("stream ended: %v", scanner.Err())
+		return e
+	}
+}
+
+func post(t *testing.T, url string, body string) {
+	res, err := http.Post(url, "application/json", strings.NewReader(body))
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func Test_server_Events(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestServer(t)
+	next := openStream(t, ts.URL+"/events?org="+folder.DefaultOrgID+"&path=golf", "")
+
+	// Only the folders entering golf match, the rename outside it is filtered out
+	post(t, ts.URL+"/orgs/"+folder.DefaultOrgID+"/folders/alpha/rename", `{"name": "alef"}`)
+	post(t, ts.URL+"/orgs/"+folder.DefaultOrgID+"/folders/bravo/move", `{"dst": "golf"}`)
+
+	e := next()
+	assert.Equal(t, "4", e.id)
+	assert.Equal(t, "moved", e.event)
+	assert.Equal(t, folder.Event{Seq: 4, Type: folder.EventMoved, OrgId: e.data.OrgId, Name: "bravo", OldPath: "alef.bravo", NewPath: "golf.bravo"}, e.data)
+	assert.Equal(t, "golf.bravo.charlie", next().data.NewPath)
+
+	// Resuming replays what came after the last id
+	resumed := openStream(t, ts.URL+"/events", "3")
+	assert.Equal(t, "4", resumed().id)
+	assert.Equal(t, "5", resumed().id)
+
+	lost := openStream(t, ts.URL+"/events?since=99", "")
+	e = lost()
+	assert.Equal(t, "lost", e.event)
+	assert.Equal(t, uint64(5), e.data.Seq)
+}
+
+func Test_server_Events_Error(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestServer(t)
+	for _, url := range []string{"/events?org=not-an-org", "/events?since=-1"} {
+		res, err := http.Get(ts.URL + url)
+		assert.NoError(t, err)
+		res.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, url)
+	}
+}
@@ -0,0 +1,90 @@
+package server_test
+
+import (
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/server"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type openAPIDocument struct {
+	Paths      map[string]map[string]yaml.Node `yaml:"paths"`
+	Components struct {
+		Schemas map[string]struct {
+			Properties map[string]any `yaml:"properties"`
+		} `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+type openAPIOperation struct {
+	Responses map[string]any `yaml:"responses"`
+}
+
+func Test_server_OpenAPI(t *testing.T) {
+	t.Parallel()
+
+	var doc openAPIDocument
+	assert.NoError(t, yaml.Unmarshal(server.OpenAPI, &doc))
+
+	t.Run("Folder schema matches the JSON shape", func(t *testing.T) {
+		want := []string{}
+		folderType := reflect.TypeFor[folder.Folder]()
+		for i := range folderType.NumField() {
+			want = append(want, strings.Split(folderType.Field(i).Tag.Get("json"), ",")[0])
+		}
+		get := []string{}
+		for name := range doc.Components.Schemas["Folder"].Properties {
+			get = append(get, name)
+		}
+		assert.ElementsMatch(t, want, get)
+	})
+
+	// Every documented operation is routed and only answers with documented status codes
+	ts := newTestServer(t)
+	for path, item := range doc.Paths {
+		for method, node := range item {
+			if method == "parameters" {
+				continue
+			}
+			var op openAPIOperation
+			assert.NoError(t, node.Decode(&op))
+
+			t.Run(strings.ToUpper(method)+" "+path, func(t *testing.T) {
+				url := strings.NewReplacer("{org}", folder.DefaultOrgID, "{name}", "zulu").Replace(path)
+				req, err := http.NewRequest(strings.ToUpper(method), ts.URL+url, strings.NewReader("{}"))
+				assert.NoError(t, err)
+				res, err := ts.Client().Do(req)
+				assert.NoError(t, err)
+				// not drained, the event stream never ends
+				res.Body.Close()
+
+				documented := []string{}
+				for status := range op.Responses {
+					documented = append(documented, status)
+				}
+				assert.True(t, slices.Contains(documented, strconv.Itoa(res.StatusCode)),
+					"status %d is not one of %v", res.StatusCode, documented)
+
+				if method != "post" {
+					return
+				}
+				// Bodies over 1 MiB are refused before they are decoded
+				body := `{"name": "` + strings.Repeat("a", 1<<20) + `"}`
+				req, err = http.NewRequest(http.MethodPost, ts.URL+url, strings.NewReader(body))
+				assert.NoError(t, err)
+				res, err = ts.Client().Do(req)
+				assert.NoError(t, err)
+				res.Body.Close()
+				assert.Equal(t, http.StatusRequestEntityTooLarge, res.StatusCode)
+				assert.Contains(t, documented, strconv.Itoa(res.StatusCode))
+			})
+		}
+	}
+}
@@ -0,0 +1,226 @@
+// Package server exposes a folder driver over HTTP with JSON bodies.
+//
+//	GET    /orgs/{org}/folders                  folders in an organisation
+//	POST   /orgs/{org}/folders                  create {"name", "parent"}
+//	GET    /orgs/{org}/folders/{name}/children  every folder below a folder
+//	GET    /orgs/{org}/folders/{name}/ancestors folders above a folder, from the root
+//	POST   /orgs/{org}/folders/{name}/move      move {"dst"}
+//	POST   /orgs/{org}/folders/{name}/rename    rename {"name"}
+//	DELETE /orgs/{org}/folders/{name}           delete a folder and its children
+//	GET    /events                              server-sent events of folder changes
+//	GET    /openapi.yaml                        OpenAPI 3 description of the above
+//
+// Errors are returned as {"error": "..."} with 400 for malformed requests,
+// 404 for missing folders, 409 for name clashes and 422 for changes the driver refuses.
+package server
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+)
+
+// OpenAPI is the OpenAPI 3 document describing every endpoint
+//
+//go:embed openapi.yaml
+var OpenAPI []byte
+
+type Server struct {
+	driver folder.IDriver
+	mux    *http.ServeMux
+}
+
+type CreateRequest struct {
+	Name string `json:"name"`
+	// Parent folder name, empty for a root folder
+	Parent string `json:"parent,omitempty"`
+}
+
+type MoveRequest struct {
+	Dst string `json:"dst"`
+}
+
+type RenameRequest struct {
+	Name string `json:"name"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+func New(driver folder.IDriver) *Server {
+	s := &Server{driver: driver, mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("GET /orgs/{org}/folders", s.getFolders)
+	s.mux.HandleFunc("POST /orgs/{org}/folders", s.createFolder)
+	s.mux.HandleFunc("GET /orgs/{org}/folders/{name}/children", s.getChildren)
+	s.mux.HandleFunc("GET /orgs/{org}/folders/{name}/ancestors", s.getAncestors)
+	s.mux.HandleFunc("POST /orgs/{org}/folders/{name}/move", s.moveFolder)
+	s.mux.HandleFunc("POST /orgs/{org}/folders/{name}/rename", s.renameFolder)
+	s.mux.HandleFunc("DELETE /orgs/{org}/folders/{name}", s.deleteFolder)
+	s.mux.HandleFunc("GET /events", s.streamEvents)
+	s.mux.HandleFunc("GET /openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write(OpenAPI)
+	})
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) getFolders(w http.ResponseWriter, r *http.Request) {
+	orgID, ok := orgParam(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.driver.GetFoldersByOrgID(orgID))
+}
+
+func (s *Server) getChildren(w http.ResponseWriter, r *http.Request) {
+	s.query(w, r, folder.IDriver.GetAllChildFolders)
+}
+
+func (s *Server) getAncestors(w http.ResponseWriter, r *http.Request) {
+	s.query(w, r, folder.IDriver.GetAncestorFolders)
+}
+
+func (s *Server) query(w http.ResponseWriter, r *http.Request, fn func(folder.IDriver, uuid.UUID, string) ([]folder.Folder, error)) {
+	orgID, ok := orgParam(w, r)
+	if !ok {
+		return
+	}
+
+	folders, err := fn(s.driver, orgID, r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, folders)
+}
+
+func (s *Server) createFolder(w http.ResponseWriter, r *http.Request) {
+	orgID, ok := orgParam(w, r)
+	if !ok {
+		return
+	}
+	var req CreateRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mutate(w, http.StatusCreated, func(d folder.IDriver) ([]folder.Folder, error) {
+		return d.CreateFolder(orgID, req.Name, req.Parent)
+	})
+}
+
+func (s *Server) moveFolder(w http.ResponseWriter, r *http.Request) {
+	orgID, ok := orgParam(w, r)
+	if !ok {
+		return
+	}
+	var req MoveRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mutate(w, http.StatusOK, func(d folder.IDriver) ([]folder.Folder, error) {
+		return d.MoveFolderInOrg(orgID, r.PathValue("name"), req.Dst)
+	})
+}
+
+func (s *Server) renameFolder(w http.ResponseWriter, r *http.Request) {
+	orgID, ok := orgParam(w, r)
+	if !ok {
+		return
+	}
+	var req RenameRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mutate(w, http.StatusOK, func(d folder.IDriver) ([]folder.Folder, error) {
+		return d.RenameFolderInOrg(orgID, r.PathValue("name"), req.Name)
+	})
+}
+
+func (s *Server) deleteFolder(w http.ResponseWriter, r *http.Request) {
+	orgID, ok := orgParam(w, r)
+	if !ok {
+		return
+	}
+
+	s.mutate(w, http.StatusOK, func(d folder.IDriver) ([]folder.Folder, error) {
+		return d.DeleteFolderInOrg(orgID, r.PathValue("name"))
+	})
+}
+
+// Applies a change and responds with the driver's folders
+func (s *Server) mutate(w http.ResponseWriter, status int, fn func(folder.IDriver) ([]folder.Folder, error)) {
+	folders, err := fn(s.driver)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, status, folders)
+}
+
+func orgParam(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	orgID, err := uuid.FromString(r.PathValue("org"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid organisation ID"})
+		return uuid.Nil, false
+	}
+	return orgID, true
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(v)
+	var maxBytes *http.MaxBytesError
+	if errors.As(err, &maxBytes) {
+		writeJSON(w, http.StatusRequestEntityTooLarge, ErrorResponse{Error: err.Error()})
+		return false
+	} else if err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid request body: " + err.Error()})
+		return false
+	}
+	return true
+}
+
+// Maps driver errors to a status code
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, folder.ErrFolderNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, folder.ErrFolderExists):
+		return http.StatusConflict
+	case errors.Is(err, folder.ErrInvalidMove), errors.Is(err, folder.ErrInvalidName):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, statusFor(err), ErrorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(b, '\n'))
+}
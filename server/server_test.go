@@ -0,0 +1,222 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/server"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	secondaryOrgID := uuid.FromStringOrNil("38b9879b-f73b-4b0e-b9d9-4fc4c23643a7")
+
+	ts := httptest.NewServer(server.New(folder.NewDriver([]folder.Folder{
+		{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+		{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+		{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+		{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+		{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+	})))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func Test_server(t *testing.T) {
+	t.Parallel()
+
+	defaultOrgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	secondaryOrgID := uuid.FromStringOrNil("38b9879b-f73b-4b0e-b9d9-4fc4c23643a7")
+	orgPath := "/orgs/" + folder.DefaultOrgID
+
+	tests := [...]struct {
+		testName string
+		method   string
+		path     string
+		body     string
+		status   int
+		want     []folder.Folder
+		err      string
+	}{
+		{
+			testName: "Folders by org",
+			method:   http.MethodGet,
+			path:     "/orgs/38b9879b-f73b-4b0e-b9d9-4fc4c23643a7/folders",
+			status:   http.StatusOK,
+			want:     []folder.Folder{{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID}},
+		},
+		{
+			testName: "Children",
+			method:   http.MethodGet,
+			path:     orgPath + "/folders/alpha/children",
+			status:   http.StatusOK,
+			want: []folder.Folder{
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+				{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+			},
+		},
+		{
+			testName: "Ancestors",
+			method:   http.MethodGet,
+			path:     orgPath + "/folders/charlie/ancestors",
+			status:   http.StatusOK,
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+			},
+		},
+		{
+			testName: "Invalid org",
+			method:   http.MethodGet,
+			path:     "/orgs/not-an-org/folders",
+			status:   http.StatusBadRequest,
+			err:      "invalid organisation ID",
+		},
+		{
+			testName: "Children of missing folder",
+			method:   http.MethodGet,
+			path:     orgPath + "/folders/zulu/children",
+			status:   http.StatusNotFound,
+			err:      "folder does not exist in the specified organisation",
+		},
+		{
+			testName: "Move",
+			method:   http.MethodPost,
+			path:     orgPath + "/folders/bravo/move",
+			body:     `{"dst": "golf"}`,
+			status:   http.StatusOK,
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "bravo", Paths: "golf.bravo", OrgId: defaultOrgID},
+				{Name: "charlie", Paths: "golf.bravo.charlie", OrgId: defaultOrgID},
+				{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+				{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+			},
+		},
+		{
+			testName: "Move to missing destination",
+			method:   http.MethodPost,
+			path:     orgPath + "/folders/bravo/move",
+			body:     `{"dst": "zulu"}`,
+			status:   http.StatusNotFound,
+			err:      "destination folder does not exist",
+		},
+		{
+			testName: "Move into a child",
+			method:   http.MethodPost,
+			path:     orgPath + "/folders/alpha/move",
+			body:     `{"dst": "charlie"}`,
+			status:   http.StatusUnprocessableEntity,
+			err:      "cannot move a folder to a child of itself",
+		},
+		{
+			testName: "Move across orgs",
+			method:   http.MethodPost,
+			path:     orgPath + "/folders/alpha/move",
+			body:     `{"dst": "foxtrot"}`,
+			status:   http.StatusNotFound,
+			err:      "destination folder does not exist in the specified organisation",
+		},
+		{
+			testName: "Malformed body",
+			method:   http.MethodPost,
+			path:     orgPath + "/folders/alpha/move",
+			body:     `{"destination": "golf"}`,
+			status:   http.StatusBadRequest,
+			err:      "invalid request body",
+		},
+		{
+			testName: "Rename to a taken name",
+			method:   http.MethodPost,
+			path:     orgPath + "/folders/bravo/rename",
+			body:     `{"name": "golf"}`,
+			status:   http.StatusConflict,
+			err:      "folder already exists in the specified organisation",
+		},
+		{
+			testName: "Rename to an invalid name",
+			method:   http.MethodPost,
+			path:     orgPath + "/folders/bravo/rename",
+			body:     `{"name": "go.lf"}`,
+			status:   http.StatusUnprocessableEntity,
+			err:      "contains '.'",
+		},
+		{
+			testName: "Create",
+			method:   http.MethodPost,
+			path:     "/orgs/38b9879b-f73b-4b0e-b9d9-4fc4c23643a7/folders",
+			body:     `{"name": "hotel", "parent": "foxtrot"}`,
+			status:   http.StatusCreated,
+			want: []folder.Folder{
+				{Name: "alpha", Paths: "alpha", OrgId: defaultOrgID},
+				{Name: "bravo", Paths: "alpha.bravo", OrgId: defaultOrgID},
+				{Name: "charlie", Paths: "alpha.bravo.charlie", OrgId: defaultOrgID},
+				{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+				{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+				{Name: "hotel", Paths: "foxtrot.hotel", OrgId: secondaryOrgID},
+			},
+		},
+		{
+			testName: "Delete",
+			method:   http.MethodDelete,
+			path:     orgPath + "/folders/alpha",
+			status:   http.StatusOK,
+			want: []folder.Folder{
+				{Name: "golf", Paths: "golf", OrgId: defaultOrgID},
+				{Name: "foxtrot", Paths: "foxtrot", OrgId: secondaryOrgID},
+			},
+		},
+		{
+			testName: "Delete missing folder",
+			method:   http.MethodDelete,
+			path:     orgPath + "/folders/zulu",
+			status:   http.StatusNotFound,
+			err:      "folder does not exist",
+		},
+		{
+			testName: "Delete in another org",
+			method:   http.MethodDelete,
+			path:     orgPath + "/folders/foxtrot",
+			status:   http.StatusNotFound,
+			err:      "folder does not exist in the specified organisation",
+		},
+		{
+			testName: "Delete with an invalid org",
+			method:   http.MethodDelete,
+			path:     "/orgs/nope/folders/alpha",
+			status:   http.StatusBadRequest,
+			err:      "invalid organisation ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			ts := newTestServer(t)
+
+			req, err := http.NewRequest(tt.method, ts.URL+tt.path, strings.NewReader(tt.body))
+			assert.NoError(t, err)
+			res, err := ts.Client().Do(req)
+			assert.NoError(t, err)
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.status, res.StatusCode)
+			assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+			if tt.err != "" {
+				var get server.ErrorResponse
+				assert.NoError(t, json.NewDecoder(res.Body).Decode(&get))
+				assert.Contains(t, get.Error, tt.err)
+				return
+			}
+			var get []folder.Folder
+			assert.NoError(t, json.NewDecoder(res.Body).Decode(&get))
+			assert.Equal(t, tt.want, get)
+		})
+	}
+}